@@ -1,25 +1,72 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cuenobi/mcp-platform/api-gateway/internal/gateway"
+	"github.com/cuenobi/mcp-platform/api-gateway/internal/tracing"
+	"github.com/cuenobi/mcp-platform/shared/tlsclient"
 )
 
+var listenAddr string
+
 var apiGatewayCmd = &cobra.Command{
 	Use:   "apiGateway",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Serve the HTTP API in front of the MCP servers",
+	Long: `Serve the HTTP API in front of the MCP servers.
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+Requests are forwarded to mcp-server-jira at MCP_SERVER_JIRA_ADDR. TLS to
+the server is configured with MCP_TLS_CA_FILE, MCP_TLS_CLIENT_CERT_FILE,
+MCP_TLS_CLIENT_KEY_FILE and MCP_TLS_SERVER_NAME. Tracing is configured with
+MCP_TRACING_EXPORTER (none, otlp or file) and related variables. Logs are
+written as JSON to stderr at MCP_LOG_LEVEL (default info).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("apiGateway called")
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(os.Getenv("MCP_LOG_LEVEL"))); err != nil {
+			level = slog.LevelInfo
+		}
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+		addr := os.Getenv("MCP_SERVER_JIRA_ADDR")
+		if addr == "" {
+			addr = "localhost:50051"
+		}
+
+		shutdownTracing, err := tracing.Setup(context.Background(), "api-gateway", tracing.OptionsFromEnv())
+		if err != nil {
+			fatal("Failed to set up tracing", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = shutdownTracing(ctx)
+		}()
+
+		client, conn, err := gateway.Dial(addr, tlsclient.FromEnv())
+		if err != nil {
+			fatal("Failed to connect to mcp-server-jira", err)
+		}
+		defer conn.Close()
+
+		slog.Info("API gateway listening", "addr", listenAddr)
+		if err := http.ListenAndServe(listenAddr, gateway.NewServer(client).Handler()); err != nil {
+			fatal("Failed to serve", err)
+		}
 	},
 }
 
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func init() {
+	apiGatewayCmd.Flags().StringVar(&listenAddr, "addr", ":8080", "HTTP listen address")
 	rootCmd.AddCommand(apiGatewayCmd)
 }
@@ -0,0 +1,26 @@
+package gateway
+
+import (
+	"fmt"
+
+	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"github.com/cuenobi/mcp-platform/shared/tlsclient"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// Dial connects to mcp-server-jira at addr.
+func Dial(addr string, tlsCfg tlsclient.Config) (pb.JiraServiceClient, *grpc.ClientConn, error) {
+	transport, err := tlsCfg.DialOption()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	conn, err := grpc.Dial(addr, transport,
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(unaryClientMetrics),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("did not connect: %w", err)
+	}
+	return pb.NewJiraServiceClient(conn), conn, nil
+}
@@ -0,0 +1,203 @@
+// Package gateway exposes the JiraService over HTTP/JSON.
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Request headers forwarded to mcp-server-jira as the caller's Jira identity.
+var forwardedHeaders = map[string]string{
+	"Authorization":    "authorization",
+	"X-Jira-Email":     "x-jira-email",
+	"X-Jira-Api-Token": "x-jira-api-token",
+	"X-Jira-Cloud-Id":  "x-jira-cloud-id",
+}
+
+// requestIDHeader carries the request ID end to end; it is forwarded to
+// mcp-server-jira as x-request-id metadata.
+const requestIDHeader = "X-Request-Id"
+
+type requestIDContextKey struct{}
+
+type Server struct {
+	client  pb.JiraServiceClient
+	timeout time.Duration
+}
+
+func NewServer(client pb.JiraServiceClient) *Server {
+	return &Server{client: client, timeout: 2 * time.Minute}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("POST /v1/messages", instrument("/v1/messages", s.message))
+	mux.HandleFunc("POST /v1/cards", instrument("/v1/cards", s.createCard))
+	return otelhttp.NewHandler(withRequestID(mux), "api-gateway", otelhttp.WithSpanNameFormatter(
+		func(_ string, r *http.Request) string { return r.Method + " " + r.URL.Path },
+	))
+}
+
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) message(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Prompt string `json:"prompt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	ctx, cancel := s.outgoingContext(r)
+	defer cancel()
+
+	resp, err := s.client.Message(ctx, &pb.MessageRequest{Prompt: body.Prompt})
+	if err != nil {
+		writeRPCError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"message":    resp.Message,
+		"intent":     resp.Intent,
+		"confidence": resp.Confidence,
+	})
+}
+
+func (s *Server) createCard(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ProjectKey string `json:"project_key"`
+		Prompt     string `json:"prompt"`
+		Language   string `json:"language"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	ctx, cancel := s.outgoingContext(r)
+	defer cancel()
+
+	resp, err := s.client.CreateCard(ctx, &pb.CreateCardRequest{
+		ProjectKey:     body.ProjectKey,
+		Prompt:         body.Prompt,
+		Language:       body.Language,
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+	})
+	if err != nil {
+		writeRPCError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"issue_key": resp.IssueKey,
+		"status":    resp.Status,
+	})
+}
+
+// withRequestID reuses the caller's X-Request-Id or generates one, and
+// echoes it on the response.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			b := make([]byte, 8)
+			_, _ = rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, id)))
+	})
+}
+
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+func (s *Server) outgoingContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	if id := requestID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", id)
+	}
+	for header, key := range forwardedHeaders {
+		if value := r.Header.Get(header); value != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+		}
+	}
+	return ctx, cancel
+}
+
+func writeRPCError(w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	slog.WarnContext(r.Context(), "RPC failed",
+		"request_id", requestID(r.Context()), "path", r.URL.Path, "code", st.Code().String(), "error", st.Message())
+
+	body := map[string]any{"error": st.Message()}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			body["reason"] = d.Reason
+		case *errdetails.BadRequest:
+			violations := make([]map[string]string, 0, len(d.FieldViolations))
+			for _, v := range d.FieldViolations {
+				violations = append(violations, map[string]string{"field": v.Field, "description": v.Description})
+			}
+			body["violations"] = violations
+		}
+	}
+	writeJSON(w, httpStatus(st.Code()), body)
+}
+
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Failed to write response", "error", err)
+	}
+}
@@ -46,8 +46,13 @@ setup_ollama() {
         sleep 5
     done
     
+    # mcp-server-jira needs both: one generates issues, the other embeds
+    # messages for intent routing. Its llm health check fails until both
+    # are pulled.
     echo "📥 Pulling llama3 model..."
     docker-compose exec ollama ollama pull llama3
+    echo "📥 Pulling nomic-embed-text embedding model..."
+    docker-compose exec ollama ollama pull nomic-embed-text
     
     echo "✅ Ollama setup complete!"
 }
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/audit"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log of Jira mutations",
+	Long: `Inspect the audit log of Jira mutations.
+
+Every create, update, transition and comment is appended to audit.file
+(default audit.jsonl) with the caller, prompt, model output, Jira payload and
+response. Rotated files (audit.jsonl.1, audit.jsonl.2, ...) are searched too.`,
+}
+
+var auditQueryFlags struct {
+	project string
+	user    string
+	action  string
+	since   string
+	until   string
+	limit   int
+	json    bool
+}
+
+var auditQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "List audit entries by project, user or time range",
+	Example: `  mcp-server-jira audit query --project AIT --since 24h
+  mcp-server-jira audit query --user alice@example.com --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Audit.File == "" {
+			return fmt.Errorf("audit log is disabled (audit.file is empty)")
+		}
+
+		now := time.Now()
+		since, err := parseTimeFlag(auditQueryFlags.since, now)
+		if err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+		until, err := parseTimeFlag(auditQueryFlags.until, now)
+		if err != nil {
+			return fmt.Errorf("--until: %w", err)
+		}
+		filter := audit.Filter{
+			Project: auditQueryFlags.project,
+			Caller:  auditQueryFlags.user,
+			Action:  auditQueryFlags.action,
+			Since:   since,
+			Until:   until,
+		}
+
+		var entries []audit.Entry
+		err = audit.Query(cfg.Audit.File, filter, func(e audit.Entry) bool {
+			entries = append(entries, e)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if limit := auditQueryFlags.limit; limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		if auditQueryFlags.json {
+			enc := json.NewEncoder(os.Stdout)
+			for _, e := range entries {
+				if err := enc.Encode(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME\tACTION\tPROJECT\tISSUE\tCALLER\tRESULT")
+		for _, e := range entries {
+			result := "ok"
+			if e.Error != "" {
+				result = e.Error
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.Time.Local().Format(time.RFC3339), e.Action, e.Project, e.IssueKey, e.Caller, result)
+		}
+		return tw.Flush()
+	},
+}
+
+// parseTimeFlag accepts an RFC 3339 timestamp, a date, or a duration meaning
+// that long before now.
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration, RFC 3339 time or YYYY-MM-DD date", value)
+}
+
+func init() {
+	f := auditQueryCmd.Flags()
+	f.StringVar(&auditQueryFlags.project, "project", "", "Only entries for this project key")
+	f.StringVar(&auditQueryFlags.user, "user", "", "Only entries whose caller contains this string")
+	f.StringVar(&auditQueryFlags.action, "action", "", "Only create, update, transition, comment or reject entries")
+	f.StringVar(&auditQueryFlags.since, "since", "", "Start of the range: duration ago (24h), RFC 3339 time or date")
+	f.StringVar(&auditQueryFlags.until, "until", "", "End of the range (exclusive), same formats as --since")
+	f.IntVar(&auditQueryFlags.limit, "limit", 0, "Show only the most recent N matches")
+	f.BoolVar(&auditQueryFlags.json, "json", false, "Print full entries as JSON lines")
+
+	auditCmd.AddCommand(auditQueryCmd)
+	rootCmd.AddCommand(auditCmd)
+}
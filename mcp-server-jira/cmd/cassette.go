@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/cassette"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/config"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/intent"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/logging"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+)
+
+// cassetteMetadata are the incoming metadata keys stored in a cassette so a
+// replay resolves credentials the same way.
+var cassetteMetadata = []string{
+	jira.MetadataJiraEmail,
+	jira.MetadataJiraAPIToken,
+	jira.MetadataJiraCloudID,
+	jira.MetadataAuthorization,
+}
+
+// cassetteSettings are the configuration values stored in a cassette and
+// restored on replay.
+var cassetteSettings = map[string]func(*config.Config) *string{
+	"jira.base_url":          func(c *config.Config) *string { return &c.Jira.BaseURL },
+	"jira.project_key":       func(c *config.Config) *string { return &c.Jira.ProjectKey },
+	"ollama.base_url":        func(c *config.Config) *string { return &c.Ollama.BaseURL },
+	"ollama.model":           func(c *config.Config) *string { return &c.Ollama.Model },
+	"ollama.routing_model":   func(c *config.Config) *string { return &c.Ollama.RoutingModel },
+	"ollama.embedding_model": func(c *config.Config) *string { return &c.Ollama.EmbeddingModel },
+}
+
+// recordCassette records each JiraService RPC and its outbound exchanges to
+// a cassette in cassettes.dir, when that is set.
+func recordCassette(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	cfg := jira.CurrentConfig()
+	if cfg.Cassettes.Dir == "" || !strings.HasPrefix(info.FullMethod, "/"+pb.JiraService_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+	redactor, err := logging.NewRedactor(cfg.Logging.RedactPatterns)
+	if err != nil {
+		slog.ErrorContext(ctx, "Not recording cassette", "error", err)
+		return handler(ctx, req)
+	}
+	scrub := cassette.Scrubber(redactor.String)
+
+	c := &cassette.Cassette{
+		Version:    cassette.Version,
+		RecordedAt: time.Now(),
+		RequestID:  logging.RequestID(ctx),
+		RPC:        info.FullMethod,
+		Metadata:   scrubMetadata(ctx, scrub),
+		Settings:   make(map[string]string),
+	}
+	for key, field := range cassetteSettings {
+		c.Settings[key] = *field(cfg)
+	}
+	if m, ok := req.(proto.Message); ok {
+		c.Request = scrubbedJSON(m, scrub)
+	}
+
+	// Responses keep email addresses: a replay must get back exactly what
+	// Jira and Ollama sent, or it would not rerun the same request.
+	recorder := cassette.NewRecorder(scrub, redactor.Credentials)
+	resp, err := handler(cassette.WithRecorder(ctx, recorder), req)
+	c.Outcome = outcomeOf(resp, err, scrub)
+	c.Interactions = recorder.Interactions()
+
+	if path, saveErr := c.Save(cfg.Cassettes.Dir); saveErr != nil {
+		slog.ErrorContext(ctx, "Failed to save cassette", "error", saveErr)
+	} else {
+		slog.InfoContext(ctx, "Recorded cassette", "file", path, "interactions", len(c.Interactions))
+	}
+	return resp, err
+}
+
+func scrubMetadata(ctx context.Context, scrub cassette.Scrubber) map[string]string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	out := make(map[string]string)
+	for _, key := range cassetteMetadata {
+		values := md.Get(key)
+		if len(values) == 0 {
+			continue
+		}
+		switch key {
+		case jira.MetadataJiraAPIToken:
+			out[key] = cassette.Mask
+		case jira.MetadataAuthorization:
+			scheme, _, _ := strings.Cut(values[0], " ")
+			out[key] = scheme + " " + cassette.Mask
+		default:
+			out[key] = scrub(values[0])
+		}
+	}
+	return out
+}
+
+// scrubbedJSON renders m as compact protobuf JSON with secrets masked. A
+// redaction pattern that breaks the JSON leaves it stored as a string.
+func scrubbedJSON(m proto.Message, scrub cassette.Scrubber) json.RawMessage {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	scrubbed := []byte(scrub(string(data)))
+	if !json.Valid(scrubbed) {
+		quoted, _ := json.Marshal(string(scrubbed))
+		return quoted
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, scrubbed); err != nil {
+		return scrubbed
+	}
+	return compact.Bytes()
+}
+
+func outcomeOf(resp any, err error, scrub cassette.Scrubber) cassette.Outcome {
+	if err != nil {
+		st := status.Convert(err)
+		return cassette.Outcome{Code: st.Code().String(), Message: scrub(st.Message())}
+	}
+	out := cassette.Outcome{Code: codes.OK.String()}
+	if m, ok := resp.(proto.Message); ok {
+		out.Response = scrubbedJSON(m, scrub)
+	}
+	return out
+}
+
+var cassetteCmd = &cobra.Command{
+	Use:   "cassette",
+	Short: "Replay RPCs recorded with --cassette-dir",
+	Long: `Replay RPCs recorded with --cassette-dir.
+
+With cassettes.dir set, the server writes one cassette per RPC: the request,
+its outcome and every Jira and Ollama exchange it caused. Credentials and
+logging.redact_patterns are masked throughout; email addresses are masked
+everywhere except in Jira and Ollama responses, which a replay must return
+unchanged. "cassette replay" reruns
+the request in-process with the current code, prompts and limits, answering
+each outbound call from the cassette. Nothing is sent to Jira or Ollama.`,
+}
+
+var cassetteReplayFlags struct {
+	strict bool
+}
+
+var cassetteReplayCmd = &cobra.Command{
+	Use:   "replay <cassette.json>",
+	Short: "Rerun a recorded RPC offline and compare the outcome",
+	Example: `  mcp-server-jira cassette replay cassettes/20261019T101500.123Z-CreateCard-4f1c2a9e0b7d3e61.json
+  mcp-server-jira cassette replay --prompts-dir ./prompts-v2 recorded.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := cassette.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Call the same URLs with the same project and models as the
+		// recording; the player answers every call.
+		for key, value := range c.Settings {
+			if field, ok := cassetteSettings[key]; ok && value != "" {
+				*field(cfg) = value
+			}
+		}
+		cfg.Cassettes.Dir = ""
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+		set, err := prompts.Load(cfg.Prompts.Dir)
+		if err != nil {
+			return err
+		}
+		jira.UsePrompts(set)
+		intents, err := intent.Load(cfg.Router.IntentsFile)
+		if err != nil {
+			return err
+		}
+		jira.UseIntents(intents)
+		jira.Configure(cfg)
+
+		name := c.RPC[strings.LastIndex(c.RPC, "/")+1:]
+		var method *grpc.MethodDesc
+		for i, m := range pb.JiraService_ServiceDesc.Methods {
+			if "/"+pb.JiraService_ServiceDesc.ServiceName+"/"+m.MethodName == c.RPC {
+				method = &pb.JiraService_ServiceDesc.Methods[i]
+			}
+		}
+		if method == nil {
+			return fmt.Errorf("cassette records %s, which this server does not implement", c.RPC)
+		}
+
+		redactor, err := logging.NewRedactor(cfg.Logging.RedactPatterns)
+		if err != nil {
+			return err
+		}
+		scrub := cassette.Scrubber(redactor.String)
+		player := cassette.NewPlayer(c, scrub, cassetteReplayFlags.strict)
+		ctx := cassette.WithPlayer(cmd.Context(), player)
+		ctx = metadata.NewIncomingContext(ctx, metadata.New(c.Metadata))
+		ctx = logging.WithRequestID(ctx, c.RequestID)
+		decode := func(v any) error {
+			return protojson.Unmarshal(c.Request, v.(proto.Message))
+		}
+		resp, err := method.Handler(&server{}, ctx, decode, validateRequests)
+		replayed := outcomeOf(resp, err, scrub)
+
+		fmt.Printf("%s recorded %s, %d exchanges\n", name, c.RecordedAt.Format(time.RFC3339), len(c.Interactions))
+		fmt.Printf("  recorded: %s\n", describeOutcome(c.Outcome))
+		fmt.Printf("  replayed: %s\n", describeOutcome(replayed))
+		for _, m := range player.Mismatches() {
+			fmt.Printf("  mismatch: %s\n", m)
+		}
+		for _, i := range player.Unused() {
+			fmt.Printf("  unused: %s %s %s\n", i.Dependency, i.Request.Method, i.Request.URL)
+		}
+
+		if !sameOutcome(c.Outcome, replayed) {
+			return errors.New("replayed outcome differs from the recording")
+		}
+		fmt.Println("Replay matches the recording.")
+		return nil
+	},
+}
+
+func describeOutcome(o cassette.Outcome) string {
+	if o.Code != codes.OK.String() {
+		return o.Code + ": " + o.Message
+	}
+	return o.Code + " " + compactJSON(o.Response)
+}
+
+func sameOutcome(a, b cassette.Outcome) bool {
+	return a.Code == b.Code && a.Message == b.Message && compactJSON(a.Response) == compactJSON(b.Response)
+}
+
+// compactJSON strips the indentation a cassette file adds to raw JSON.
+func compactJSON(raw json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+func init() {
+	cassetteReplayCmd.Flags().BoolVar(&cassetteReplayFlags.strict, "strict", false, "Fail outbound calls whose request body differs from the recording")
+	cassetteCmd.AddCommand(cassetteReplayCmd)
+	rootCmd.AddCommand(cassetteCmd)
+}
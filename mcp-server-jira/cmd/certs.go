@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tlsconfig"
+)
+
+var (
+	certsDir   string
+	certsHosts []string
+	certsDays  int
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage TLS certificates",
+}
+
+var certsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a development CA with server and client certificates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validFor := time.Duration(certsDays) * 24 * time.Hour
+		if err := tlsconfig.GenerateDevCerts(certsDir, certsHosts, validFor); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote ca.pem, server.pem and client.pem (with keys) to %s\n", certsDir)
+		return nil
+	},
+}
+
+func init() {
+	certsGenerateCmd.Flags().StringVar(&certsDir, "dir", "certs", "Output directory")
+	certsGenerateCmd.Flags().StringSliceVar(&certsHosts, "hosts", []string{"localhost", "127.0.0.1", "mcp-server-jira"}, "Server certificate host names and IPs")
+	certsGenerateCmd.Flags().IntVar(&certsDays, "days", 365, "Certificate validity in days")
+	certsCmd.AddCommand(certsGenerateCmd)
+	rootCmd.AddCommand(certsCmd)
+}
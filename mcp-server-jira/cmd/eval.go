@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/eval"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/intent"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Score the intent router and issue-generation prompt against a dataset",
+	Long: `Score the intent router and issue-generation prompt against a dataset.
+
+A dataset is a YAML list of messages with the expected intent (an intent
+name such as create_issue, or clarify or reject) and, optionally, properties
+the generated card must have. "eval run" sends every message through the
+prompt guard, the intent router and, for cards, the generation prompt using
+the configured models, router.intents_file and prompts.dir. No Jira issue is
+created. Save runs with --output and compare two of them with "eval diff" to
+see what a prompt, example or model change did.`,
+}
+
+var evalRunFlags struct {
+	dataset string
+	output  string
+	label   string
+	verbose bool
+}
+
+var evalRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run a dataset through the configured model and prompts",
+	Example: `  mcp-server-jira eval run --dataset eval.example.yaml --output baseline.json
+  mcp-server-jira eval run --dataset eval.example.yaml --prompts-dir ./prompts-v2 --output v2.json
+  mcp-server-jira eval run --dataset eval.example.yaml --intents-file intents.yaml --output intents.json
+  mcp-server-jira eval run --dataset eval.example.yaml --embedding-model mxbai-embed-large --output mxbai.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+		ds, err := eval.LoadDataset(evalRunFlags.dataset)
+		if err != nil {
+			return err
+		}
+		set, err := prompts.Load(cfg.Prompts.Dir)
+		if err != nil {
+			return err
+		}
+		jira.UsePrompts(set)
+		intents, err := intent.Load(cfg.Router.IntentsFile)
+		if err != nil {
+			return err
+		}
+		jira.UseIntents(intents)
+		jira.Configure(cfg)
+
+		total := len(ds.Cases)
+		done := 0
+		report, err := eval.Run(cmd.Context(), ds, func(r eval.Result) {
+			done++
+			if evalRunFlags.verbose {
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s: %s\n", done, total, r.Name, resultLine(r))
+			}
+		})
+		if err != nil {
+			return err
+		}
+		report.Dataset = evalRunFlags.dataset
+		report.Label = evalRunFlags.label
+		if report.Label == "" {
+			report.Label = defaultLabel(report)
+		}
+
+		if evalRunFlags.output != "" {
+			if err := report.Save(evalRunFlags.output); err != nil {
+				return err
+			}
+		}
+		return eval.WriteSummary(os.Stdout, report)
+	},
+}
+
+func resultLine(r eval.Result) string {
+	if r.Error != "" {
+		return "error: " + r.Error
+	}
+	line := fmt.Sprintf("intent %s (%.2f)", r.Intent, r.Confidence)
+	if r.Card != nil {
+		line += ", card " + fmt.Sprintf("%q", r.Card.Title)
+	}
+	return line
+}
+
+func defaultLabel(r *eval.Report) string {
+	parts := []string{r.EmbeddingModel, r.Model}
+	if r.Intents != "builtin" {
+		parts = append(parts, filepath.Base(r.Intents))
+	}
+	for _, name := range prompts.Names {
+		parts = append(parts, r.Templates[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+var evalDiffCmd = &cobra.Command{
+	Use:     "diff <baseline.json> <candidate.json>",
+	Short:   "Compare two saved eval runs",
+	Example: `  mcp-server-jira eval diff baseline.json v2.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, err := eval.LoadReport(args[0])
+		if err != nil {
+			return err
+		}
+		cand, err := eval.LoadReport(args[1])
+		if err != nil {
+			return err
+		}
+		return eval.WriteDiff(os.Stdout, base, cand)
+	},
+}
+
+func init() {
+	f := evalRunCmd.Flags()
+	f.StringVarP(&evalRunFlags.dataset, "dataset", "d", "", "YAML dataset of messages and expected outcomes")
+	f.StringVarP(&evalRunFlags.output, "output", "o", "", "Write the full report as JSON for eval diff")
+	f.StringVar(&evalRunFlags.label, "label", "", "Name for this run in reports (default: models and template versions)")
+	f.BoolVarP(&evalRunFlags.verbose, "verbose", "v", false, "Print each case as it completes")
+	evalRunCmd.MarkFlagRequired("dataset")
+	evalCmd.AddCommand(evalRunCmd)
+	evalCmd.AddCommand(evalDiffCmd)
+	rootCmd.AddCommand(evalCmd)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/config"
+	"github.com/cuenobi/mcp-platform/shared/tlsclient"
+)
+
+// Health service names reported by the server. The empty name is the overall
+// status; it is SERVING only when every dependency is.
+const (
+	healthJira    = "jira"
+	healthLLM     = "llm"
+	healthService = "jira.JiraService"
+)
+
+type dependencyCheck struct {
+	service string
+	check   func(context.Context) error
+}
+
+var dependencyChecks = []dependencyCheck{
+	{service: healthJira, check: jira.CheckJira},
+	{service: healthLLM, check: jira.CheckLLM},
+}
+
+// runHealthChecks probes every dependency each interval until ctx is done.
+func runHealthChecks(ctx context.Context, hs *health.Server) {
+	for _, name := range []string{"", healthService, healthJira, healthLLM} {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	last := map[string]error{}
+	probe := func() {
+		hc := jira.CurrentConfig().Health
+		healthy := true
+		for _, dep := range dependencyChecks {
+			checkCtx, cancel := context.WithTimeout(ctx, hc.Timeout)
+			err := dep.check(checkCtx)
+			cancel()
+
+			status := healthpb.HealthCheckResponse_SERVING
+			if err != nil {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				healthy = false
+			}
+			if prev, seen := last[dep.service]; !seen || (prev == nil) != (err == nil) {
+				if err != nil {
+					slog.Warn("Dependency unhealthy", "service", dep.service, "status", "NOT_SERVING", "error", err)
+				} else {
+					slog.Info("Dependency healthy", "service", dep.service, "status", "SERVING")
+				}
+			}
+			last[dep.service] = err
+			hs.SetServingStatus(dep.service, status)
+		}
+
+		overall := healthpb.HealthCheckResponse_SERVING
+		if !healthy {
+			overall = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		hs.SetServingStatus("", overall)
+		hs.SetServingStatus(healthService, overall)
+	}
+
+	probe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jira.CurrentConfig().Health.Interval):
+			probe()
+		}
+	}
+}
+
+var (
+	healthTarget  string
+	healthName    string
+	healthTimeout time.Duration
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Query a running server's gRPC health service",
+	Long: `Query a running server's gRPC health service and exit non-zero unless the
+requested service is SERVING. Use --service jira or --service llm to check a
+single dependency.
+
+TLS settings come from the same config file, environment and flags as the
+server: the check uses TLS whenever tls.cert_file is set, verifies the server
+with tls.ca_file and presents tls.client_cert_file for mutual TLS.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		transport, err := healthTLS(cfg).DialOption()
+		if err != nil {
+			return err
+		}
+
+		conn, err := grpc.Dial(healthTarget, transport)
+		if err != nil {
+			return fmt.Errorf("did not connect: %w", err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+		defer cancel()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: healthName})
+		if err != nil {
+			return fmt.Errorf("health check failed: %w", err)
+		}
+		fmt.Println(resp.Status)
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("service %q is %s", healthName, resp.Status)
+		}
+		return nil
+	},
+}
+
+// healthTLS derives the client side of cfg's TLS settings. A server with a
+// certificate only accepts TLS, so the check uses it whenever tls.cert_file
+// is set; without tls.ca_file the system roots verify the server.
+func healthTLS(cfg *config.Config) tlsclient.Config {
+	return tlsclient.Config{
+		Enabled:    tlsFiles(cfg).Enabled() || cfg.TLS.CAFile != "" || cfg.TLS.ClientCertFile != "",
+		CAFile:     cfg.TLS.CAFile,
+		CertFile:   cfg.TLS.ClientCertFile,
+		KeyFile:    cfg.TLS.ClientKeyFile,
+		ServerName: cfg.TLS.ServerName,
+	}
+}
+
+func init() {
+	healthCmd.Flags().StringVar(&healthTarget, "target", "localhost:50051", "Server address")
+	healthCmd.Flags().StringVar(&healthName, "service", "", "Service to check (empty for overall status)")
+	healthCmd.Flags().DurationVar(&healthTimeout, "timeout", 5*time.Second, "Request timeout")
+	rootCmd.AddCommand(healthCmd)
+}
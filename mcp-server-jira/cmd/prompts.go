@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+)
+
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "Inspect the LLM prompt templates",
+	Long: `Inspect the LLM prompt templates.
+
+Prompts are text/template files: generate.tmpl writes an issue from a
+request, shorten.tmpl summarises a description that came back over
+limits.description_max_length and jql.tmpl translates a search question
+into JQL. The built-in versions are compiled in; prompts.dir may replace
+any of them, and prompts.dir/projects/<KEY>/ overrides
+them for one Jira project. A template declares its version with a leading
+{{/* version: X */}} comment, which is recorded with every generated card.`,
+}
+
+var promptsRenderFlags struct {
+	project string
+	message string
+}
+
+var promptsRenderCmd = &cobra.Command{
+	Use:   "render <" + strings.Join(prompts.Names, "|") + ">",
+	Short: "Print the final prompt sent to the model for a message",
+	Example: `  mcp-server-jira prompts render generate --project AIT --message "Add SSO login"
+  mcp-server-jira prompts render generate --language th --bilingual --message "Add SSO login"
+  mcp-server-jira prompts render shorten --message - < long-description.txt
+  mcp-server-jira prompts render jql --project AIT --message "my open bugs"
+  echo "Add SSO login" | mcp-server-jira prompts render generate --message -`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: prompts.Names,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+		set, err := prompts.Load(cfg.Prompts.Dir)
+		if err != nil {
+			return err
+		}
+		jira.UsePrompts(set)
+		jira.Configure(cfg)
+
+		message := promptsRenderFlags.message
+		if message == "-" {
+			in, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			message = strings.TrimRight(string(in), "\n")
+		}
+		project := promptsRenderFlags.project
+		if project == "" {
+			project = cfg.Jira.ProjectKey
+		}
+
+		t, err := set.Lookup(args[0], project)
+		if err != nil {
+			return err
+		}
+		prompt, _, err := jira.RenderPrompt(args[0], project, message, "")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "# template %s from %s\n", t.ID(), t.Source)
+		fmt.Println(prompt)
+		return nil
+	},
+}
+
+func init() {
+	f := promptsRenderCmd.Flags()
+	f.StringVarP(&promptsRenderFlags.project, "project", "p", "", "Jira project key whose overrides apply (default jira.project_key)")
+	f.StringVar(&promptsRenderFlags.message, "message", "", "User message to render (the description, for shorten), or - to read it from stdin")
+	promptsCmd.AddCommand(promptsRenderCmd)
+	rootCmd.AddCommand(promptsCmd)
+}
@@ -2,16 +2,42 @@ package cmd
 
 import (
 	"context"
-	"log"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
-	"strings"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 
 	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/audit"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/config"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/idempotency"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/intent"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/logging"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/resilience"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/secrets"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tlsconfig"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/validate"
 )
 
 type server struct {
@@ -19,30 +45,58 @@ type server struct {
 }
 
 func (s *server) SyncIssues(ctx context.Context, req *pb.SyncRequest) (*pb.SyncResponse, error) {
-	log.Printf("Syncing project: %s", req.ProjectKey)
-	time.Sleep(time.Second)
+	slog.InfoContext(ctx, "Syncing project", "project", req.ProjectKey)
+	select {
+	case <-time.After(time.Second):
+	case <-ctx.Done():
+		return nil, rpcError(ctx.Err())
+	}
 	return &pb.SyncResponse{Status: "synced"}, nil
 }
 
 func (s *server) CreateCard(ctx context.Context, req *pb.CreateCardRequest) (*pb.CreateCardResponse, error) {
-	log.Printf("CreateCard called with prompt: %s", req.Prompt)
-
-	issueIdea, err := jira.GenerateIssueIdea(req.Prompt)
+	creds, err := callerCredentials(ctx)
 	if err != nil {
 		return nil, err
 	}
+	slog.InfoContext(ctx, "CreateCard called", "caller", creds.Caller(), "project", req.ProjectKey)
+	slog.DebugContext(ctx, "CreateCard prompt", "prompt", req.Prompt)
 
-	issueIdea.Title = strings.ReplaceAll(issueIdea.Title, "\n", " ")
-	if len(issueIdea.Title) > 255 {
-		issueIdea.Title = issueIdea.Title[:255]
+	if req.IdempotencyKey == "" {
+		return createCard(ctx, creds, req)
 	}
-	if len(issueIdea.Description) > 1000 {
-		issueIdea.Description = issueIdea.Description[:1000]
+
+	// Keys are scoped to the caller, the Jira user behind basic auth or the
+	// OAuth token's accountId, so one user can't replay another's card.
+	key := creds.Caller() + "\x00" + req.IdempotencyKey
+	resp, replayed, err := cardResults.Do(ctx, key, cardFingerprint(req), func() (*pb.CreateCardResponse, error) {
+		return createCard(ctx, creds, req)
+	})
+	switch {
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return nil, jira.InvalidArgumentStatus("idempotency_key", err.Error()).Err()
+	case err != nil:
+		return nil, rpcError(err)
 	}
+	if replayed {
+		slog.InfoContext(ctx, "Replaying CreateCard result for idempotency key", "issue_key", resp.IssueKey)
+	}
+	return resp, nil
+}
 
-	issueKey, err := jira.CreateIssue(req.ProjectKey, issueIdea.Title, issueIdea.Description)
+func createCard(ctx context.Context, creds *jira.Credentials, req *pb.CreateCardRequest) (*pb.CreateCardResponse, error) {
+	if err := jira.ScreenPrompt(ctx, creds, req.ProjectKey, req.Prompt); err != nil {
+		return nil, rpcError(err)
+	}
+
+	issueIdea, err := jira.GenerateIssueIdea(ctx, req.ProjectKey, req.Prompt, req.Language)
 	if err != nil {
-		return nil, err
+		return nil, rpcError(err)
+	}
+
+	issueKey, err := jira.CreateIssue(ctx, creds, req.ProjectKey, issueIdea)
+	if err != nil {
+		return nil, rpcError(err)
 	}
 
 	return &pb.CreateCardResponse{
@@ -52,39 +106,491 @@ func (s *server) CreateCard(ctx context.Context, req *pb.CreateCardRequest) (*pb
 }
 
 func (s *server) Message(ctx context.Context, req *pb.MessageRequest) (*pb.MessageResponse, error) {
-	log.Printf("Message called with prompt: %s", req.Prompt)
+	creds, err := callerCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Message called", "caller", creds.Caller())
+	slog.DebugContext(ctx, "Message prompt", "prompt", req.Prompt)
+
+	reply, err := jira.ReceivePrompt(ctx, req.Prompt, creds)
+	if err != nil {
+		return nil, rpcError(err)
+	}
 
-	response, err := jira.ReceivePrompt(req.Prompt)
+	resp := &pb.MessageResponse{
+		Message:    reply.Message,
+		Confidence: reply.Confidence,
+	}
+	if reply.Intent != intent.Clarify {
+		resp.Intent = reply.Intent
+	}
+	return resp, nil
+}
+
+func (s *server) SearchIssues(ctx context.Context, req *pb.SearchIssuesRequest) (*pb.SearchIssuesResponse, error) {
+	creds, err := callerCredentials(ctx)
 	if err != nil {
 		return nil, err
 	}
+	slog.InfoContext(ctx, "SearchIssues called", "caller", creds.Caller(), "natural_language", req.Question != "")
+	slog.DebugContext(ctx, "SearchIssues query", "jql", req.Jql, "question", req.Question)
 
-	return &pb.MessageResponse{
-		Message: response,
-	}, nil
+	result, err := jira.SearchIssues(ctx, creds, jira.SearchQuery{
+		JQL:        req.Jql,
+		Question:   req.Question,
+		ProjectKey: req.ProjectKey,
+		StartAt:    int(req.StartAt),
+		MaxResults: int(req.MaxResults),
+		Fields:     req.Fields,
+	})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = jira.DefaultSearchFields
+	}
+	resp := &pb.SearchIssuesResponse{
+		Jql:     result.JQL,
+		Total:   int32(result.Total),
+		StartAt: int32(result.StartAt),
+	}
+	for _, is := range result.Issues {
+		resp.Issues = append(resp.Issues, issueProto(is, fields))
+	}
+	if next := result.StartAt + len(result.Issues); len(result.Issues) > 0 && next < result.Total {
+		resp.NextStartAt = int32(next)
+	}
+	return resp, nil
+}
+
+func (s *server) GetIssue(ctx context.Context, req *pb.GetIssueRequest) (*pb.GetIssueResponse, error) {
+	creds, err := callerCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "GetIssue called", "caller", creds.Caller(), "issue_key", req.IssueKey)
+
+	detail, err := jira.GetIssue(ctx, creds, req.IssueKey)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	resp := &pb.GetIssueResponse{
+		Issue:     issueProto(detail.Issue, validate.IssueFields),
+		ParentKey: detail.ParentKey,
+	}
+	for _, c := range detail.Comments {
+		resp.Comments = append(resp.Comments, &pb.Comment{Id: c.ID, Author: c.Author, Body: c.Body, Created: timestamp(c.Created)})
+	}
+	for _, l := range detail.Links {
+		resp.Links = append(resp.Links, &pb.IssueLink{Relation: l.Relation, Issue: issueProto(l.Issue, relatedIssueFields)})
+	}
+	for _, sub := range detail.Subtasks {
+		resp.Subtasks = append(resp.Subtasks, issueProto(sub, relatedIssueFields))
+	}
+	return resp, nil
+}
+
+// relatedIssueFields are the fields Jira returns for linked issues and
+// subtasks.
+var relatedIssueFields = []string{"summary", "status", "issuetype", "priority"}
+
+// issueProto converts is, keeping only fields. Jira may return more fields
+// than were asked for.
+func issueProto(is jira.Issue, fields []string) *pb.Issue {
+	out := &pb.Issue{Key: is.Key}
+	for _, f := range fields {
+		switch f {
+		case "summary":
+			out.Summary = is.Summary
+		case "status":
+			out.Status = is.Status
+		case "issuetype":
+			out.IssueType = is.IssueType
+		case "assignee":
+			out.Assignee = is.Assignee
+		case "reporter":
+			out.Reporter = is.Reporter
+		case "priority":
+			out.Priority = is.Priority
+		case "labels":
+			out.Labels = is.Labels
+		case "created":
+			out.Created = timestamp(is.Created)
+		case "updated":
+			out.Updated = timestamp(is.Updated)
+		case "description":
+			out.Description = is.Description
+		}
+	}
+	return out
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// rpcError converts an error from the LLM or Jira layer into a status with
+// the closest gRPC code and a google.rpc.ErrorInfo, plus a BadRequest with
+// Jira's field errors when there are any.
+func rpcError(err error) error {
+	var apiErr *jira.APIError
+	var policyErr *jira.PolicyError
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.As(err, &apiErr):
+		return apiErr.GRPCStatus().Err()
+	case errors.As(err, &policyErr):
+		return policyErr.GRPCStatus().Err()
+	case errors.Is(err, resilience.ErrCircuitOpen):
+		return jira.ErrorStatus(codes.Unavailable, err.Error(), "CIRCUIT_OPEN", nil).Err()
+	case errors.Is(err, resilience.ErrUnavailable):
+		return jira.ErrorStatus(codes.Unavailable, err.Error(), "DEPENDENCY_UNAVAILABLE", nil).Err()
+	case errors.As(err, &urlErr):
+		return jira.ErrorStatus(codes.Unavailable, err.Error(), "DEPENDENCY_UNREACHABLE", map[string]string{
+			"host": hostOf(urlErr.URL),
+		}).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return jira.ErrorStatus(codes.Internal, err.Error(), "INTERNAL", nil).Err()
+}
+
+// callerCredentials resolves and identifies the Jira user of a request,
+// returning a gRPC status on failure.
+func callerCredentials(ctx context.Context) (*jira.Credentials, error) {
+	creds, err := jira.CredentialsFromContext(ctx)
+	if err != nil {
+		return nil, credentialsError(err)
+	}
+	if err := jira.IdentifyCaller(ctx, creds); err != nil {
+		return nil, rpcError(err)
+	}
+	return creds, nil
+}
+
+// credentialsError reports a request whose Jira credentials are missing or
+// malformed.
+func credentialsError(err error) error {
+	reason := "INVALID_CREDENTIALS"
+	if errors.Is(err, jira.ErrNoCredentials) {
+		reason = "CREDENTIALS_REQUIRED"
+	}
+	return jira.ErrorStatus(codes.Unauthenticated, err.Error(), reason, nil).Err()
+}
+
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+// validateRequests rejects malformed requests before they reach a handler,
+// using the limits of the current configuration.
+var validateRequests = validate.UnaryServerInterceptor(func() validate.Rules {
+	return validate.Rules{PromptMaxLength: jira.CurrentConfig().Limits.PromptMaxLength}
+})
+
+// cardResults replays CreateCard results by idempotency key.
+var cardResults = idempotency.New[*pb.CreateCardResponse](config.Default().Idempotency.TTL)
+
+// cardFingerprint identifies the request an idempotency key was first used
+// with.
+func cardFingerprint(req *pb.CreateCardRequest) string {
+	sum := sha256.Sum256([]byte(req.ProjectKey + "\x00" + req.Prompt + "\x00" + req.Language))
+	return hex.EncodeToString(sum[:])
 }
 
+var cfg *config.Config
+
 var rootCmd = &cobra.Command{
 	Use:   "mcp-server-jira",
 	Short: "Run Jira gRPC server",
+	// Execute reports errors through cobra.CheckErr.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		loaded, err := config.Load(cmd.Flags())
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+		return installLogger(cfg)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		log.Println("Starting Jira gRPC server...")
+		slog.Info("Starting Jira gRPC server")
 
-		lis, err := net.Listen("tcp", ":50051")
+		if err := applyConfig(cfg); err != nil {
+			fatal("Invalid configuration", err)
+		}
+
+		lis, err := net.Listen("tcp", cfg.Server.Addr)
 		if err != nil {
-			log.Fatalf("failed to listen: %v", err)
+			fatal("Failed to listen", err)
+		}
+
+		var opts []grpc.ServerOption
+		if files := tlsFiles(cfg); files.Enabled() {
+			tlsCfg, err := tlsconfig.Server(files)
+			if err != nil {
+				fatal("Failed to load TLS config", err)
+			}
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+			slog.Info("TLS enabled", "mutual_tls", files.MutualTLS())
 		}
 
-		grpcServer := grpc.NewServer()
+		shutdownTracing, err := tracing.Setup(context.Background(), "mcp-server-jira", tracing.Options{
+			Exporter:    cfg.Tracing.Exporter,
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			File:        cfg.Tracing.File,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			fatal("Failed to set up tracing", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				slog.Error("Failed to flush traces", "error", err)
+			}
+		}()
+
+		opts = append(opts,
+			grpc.StatsHandler(otelgrpc.NewServerHandler()),
+			grpc.ChainUnaryInterceptor(
+				logging.UnaryServerInterceptor,
+				recordCassette,
+				metrics.UnaryServerInterceptor,
+				validateRequests,
+			),
+		)
+		grpcServer := grpc.NewServer(opts...)
 		pb.RegisterJiraServiceServer(grpcServer, &server{})
 
-		log.Println("Listening on :50051")
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("failed to serve: %v", err)
+		healthServer := health.NewServer()
+		healthpb.RegisterHealthServer(grpcServer, healthServer)
+		reflection.Register(grpcServer)
+
+		backgroundCtx, stopBackground := context.WithCancel(context.Background())
+		defer stopBackground()
+		go runHealthChecks(backgroundCtx, healthServer)
+
+		if cfg.Metrics.Addr != "" {
+			go metrics.Serve(backgroundCtx, cfg.Metrics.Addr)
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		defer signal.Stop(signals)
+
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- grpcServer.Serve(lis)
+		}()
+		slog.Info("Listening", "addr", cfg.Server.Addr)
+
+		for {
+			select {
+			case err := <-serveErr:
+				if err != nil {
+					fatal("Failed to serve", err)
+				}
+				return
+			case sig := <-signals:
+				if sig == syscall.SIGHUP {
+					reloadConfig(cmd)
+					continue
+				}
+				slog.Info("Draining in-flight requests", "signal", sig.String(), "timeout", cfg.Server.ShutdownTimeout)
+				stopBackground()
+				healthServer.Shutdown()
+				shutdown(grpcServer, cfg.Server.ShutdownTimeout)
+				if auditLog := jira.UseAuditLog(nil); auditLog != nil {
+					auditLog.Close()
+				}
+				slog.Info("Server stopped")
+				return
+			}
 		}
 	},
 }
 
+// resources holds everything built from a configuration that can fail to
+// load. applyConfig builds a complete set before installing any of it, so a
+// reload that fails part way leaves the running server untouched.
+type resources struct {
+	prompts  *prompts.Set
+	intents  *intent.Set
+	store    *secrets.Store
+	auditLog *audit.Log
+}
+
+// buildResources loads the prompt templates, intents, secret store and audit
+// log named by cfg. It opens the audit log last so nothing needs closing when
+// an earlier step fails.
+func buildResources(cfg *config.Config) (*resources, error) {
+	var (
+		r   resources
+		err error
+	)
+	if r.prompts, err = prompts.Load(cfg.Prompts.Dir); err != nil {
+		return nil, fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+	if r.intents, err = intent.Load(cfg.Router.IntentsFile); err != nil {
+		return nil, fmt.Errorf("failed to load intents: %w", err)
+	}
+	if secrets.Configured(cfg.Secrets.KeyFile) {
+		if r.store, err = secrets.OpenWithKeyFile(cfg.Secrets.File, cfg.Secrets.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to open secret store: %w", err)
+		}
+	}
+	if cfg.Audit.File != "" {
+		if r.auditLog, err = audit.Open(cfg.Audit.File, int64(cfg.Audit.MaxSizeMB)<<20, cfg.Audit.MaxBackups); err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+	}
+	return &r, nil
+}
+
+// applyConfig validates cfg and makes it the active configuration, reopening
+// the secret store so rotated credentials take effect. Nothing is swapped
+// unless every resource cfg names loads.
+func applyConfig(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	r, err := buildResources(cfg)
+	if err != nil {
+		return err
+	}
+
+	jira.UseSecretStore(r.store)
+	jira.UsePrompts(r.prompts)
+	jira.UseIntents(r.intents)
+	cardResults.SetTTL(cfg.Idempotency.TTL)
+	jira.Configure(cfg)
+	// UseAuditLog waits for in-flight appends, so closing the old log here
+	// cannot fail a mutation that is still being recorded.
+	if previous := jira.UseAuditLog(r.auditLog); previous != nil {
+		if err := previous.Close(); err != nil {
+			slog.Warn("Failed to close previous audit log", "error", err)
+		}
+	}
+
+	if r.store != nil {
+		slog.Info("Resolving credentials from secret store", "file", cfg.Secrets.File)
+	} else {
+		slog.Info("No secret store configured; reading credentials from configuration")
+	}
+	if r.auditLog != nil {
+		slog.Info("Recording Jira mutations", "audit_file", cfg.Audit.File)
+	} else {
+		slog.Warn("Audit log disabled; Jira mutations are not recorded")
+	}
+	if cfg.Prompts.Dir != "" {
+		slog.Info("Loaded prompt templates", "dir", cfg.Prompts.Dir)
+	}
+	if cfg.Cassettes.Dir != "" {
+		slog.Warn("Recording Jira and Ollama exchanges of every RPC", "cassette_dir", cfg.Cassettes.Dir)
+	}
+	if cfg.Router.IntentsFile != "" {
+		slog.Info("Loaded intents", "file", cfg.Router.IntentsFile, "intents", len(r.intents.Intents))
+	}
+	return nil
+}
+
+// reloadConfig re-reads the config file and environment on SIGHUP. The
+// listen address and TLS settings only change on restart. A reload that
+// fails at any step keeps the whole current configuration, logger included.
+func reloadConfig(cmd *cobra.Command) {
+	slog.Info("Received SIGHUP, reloading configuration")
+
+	next, err := config.Load(cmd.Flags())
+	if err == nil {
+		var logger *slog.Logger
+		if logger, err = newLogger(next); err == nil {
+			if err = applyConfig(next); err == nil {
+				slog.SetDefault(logger)
+			}
+		}
+	}
+	if err != nil {
+		slog.Error("Reload failed, keeping current configuration", "error", err)
+		return
+	}
+
+	if next.Server.Addr != cfg.Server.Addr || tlsFiles(next) != tlsFiles(cfg) {
+		slog.Warn("Listen address and TLS changes take effect after a restart")
+	}
+	cfg = next
+	slog.Info("Configuration reloaded")
+}
+
+// shutdown stops accepting new RPCs and waits up to timeout for in-flight
+// ones to finish before closing remaining connections.
+func shutdown(grpcServer *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		slog.Warn("Drain timeout exceeded, forcing shutdown")
+		grpcServer.Stop()
+	}
+}
+
+// installLogger makes a redacting slog logger built from cfg the process
+// default.
+func installLogger(cfg *config.Config) error {
+	logger, err := newLogger(cfg)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+func newLogger(cfg *config.Config) (*slog.Logger, error) {
+	return logging.New(os.Stderr, logging.Options{
+		Level:          cfg.Logging.Level,
+		Format:         cfg.Logging.Format,
+		RedactPatterns: cfg.Logging.RedactPatterns,
+	})
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+func tlsFiles(cfg *config.Config) tlsconfig.Files {
+	return tlsconfig.Files{
+		CertFile:     cfg.TLS.CertFile,
+		KeyFile:      cfg.TLS.KeyFile,
+		ClientCAFile: cfg.TLS.ClientCAFile,
+	}
+}
+
 func Execute() {
 	cobra.CheckErr(rootCmd.Execute())
 }
+
+func init() {
+	config.BindFlags(rootCmd.PersistentFlags())
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/secrets"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage the encrypted credential store",
+	Long: `Manage the encrypted credential store.
+
+The store file is secrets.file (default secrets.enc) and is encrypted with
+the key in MCP_SECRETS_KEY or the file named by secrets.key_file. Use
+"secrets init" to generate a key. While a key is configured, the service
+account's JIRA_EMAIL and JIRA_API_TOKEN are read from the store only.`,
+}
+
+var secretsInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a new store key",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			return err
+		}
+		if path := cfg.Secrets.KeyFile; path != "" {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("key file %s already exists", path)
+			}
+			if err := secrets.WriteFileAtomic(path, []byte(key+"\n")); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote new key to %s\n", path)
+			return nil
+		}
+		fmt.Println(key)
+		return nil
+	},
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set NAME [VALUE]",
+	Short: "Store a secret; the value is read from stdin when omitted",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openSecretStore()
+		if err != nil {
+			return err
+		}
+
+		var value string
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("failed to read secret from stdin: %w", err)
+			}
+			value = strings.TrimRight(line, "\r\n")
+		}
+
+		store.Set(args[0], value)
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Stored %s\n", args[0])
+		return nil
+	},
+}
+
+var secretsGetCmd = &cobra.Command{
+	Use:   "get NAME",
+	Short: "Print a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openSecretStore()
+		if err != nil {
+			return err
+		}
+		value, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored secret names",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openSecretStore()
+		if err != nil {
+			return err
+		}
+		for _, name := range store.List() {
+			fmt.Println(name)
+		}
+		return nil
+	},
+}
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-encrypt the store under a freshly generated key",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openSecretStore()
+		if err != nil {
+			return err
+		}
+		encoded, err := secrets.GenerateKey()
+		if err != nil {
+			return err
+		}
+		key, err := secrets.DecodeKey(encoded)
+		if err != nil {
+			return err
+		}
+		if err := store.Rotate(key); err != nil {
+			return err
+		}
+
+		if path := cfg.Secrets.KeyFile; path != "" && os.Getenv(secrets.KeyEnv) == "" {
+			if err := secrets.WriteFileAtomic(path, []byte(encoded+"\n")); err != nil {
+				return fmt.Errorf("store re-encrypted but writing new key failed, new key is %s: %w", encoded, err)
+			}
+			fmt.Printf("Rotated key written to %s\n", path)
+			return nil
+		}
+		fmt.Printf("Store re-encrypted. Update %s to:\n%s\n", secrets.KeyEnv, encoded)
+		return nil
+	},
+}
+
+func openSecretStore() (*secrets.Store, error) {
+	return secrets.OpenWithKeyFile(cfg.Secrets.File, cfg.Secrets.KeyFile)
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsInitCmd)
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsGetCmd)
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsRotateCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
@@ -0,0 +1,714 @@
+// Package jira is an in-memory emulator of the parts of the Jira Cloud REST
+// API v2 that mcp-server-jira uses: creating, reading and searching issues,
+// checking JQL, comments, transitions, issue links and subtasks, plus the
+// endpoints probed by health checks.
+//
+// It validates requests the way Jira does closely enough to exercise error
+// handling (field errors on create, 404 for unknown issues, 401 without
+// credentials) and can inject failures for retry and breaker tests.
+package jira
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Workflow statuses and the IDs of the transitions into them. Every
+// transition is available from every status.
+var transitions = []struct {
+	ID, Name, Status, Category string
+}{
+	{"11", "To Do", "To Do", "new"},
+	{"21", "In Progress", "In Progress", "indeterminate"},
+	{"31", "Done", "Done", "done"},
+}
+
+var issueTypes = []string{"Task", "Bug", "Story", "Epic", "Sub-task"}
+
+// linkTypes are the issue link types, named as Jira names them, with the
+// phrases used from each end of a link.
+var linkTypes = map[string]struct{ Inward, Outward string }{
+	"Blocks":    {"is blocked by", "blocks"},
+	"Cloners":   {"is cloned by", "clones"},
+	"Duplicate": {"is duplicated by", "duplicates"},
+	"Relates":   {"relates to", "relates to"},
+}
+
+// timeFormat is the timestamp format of Jira REST responses.
+const timeFormat = "2006-01-02T15:04:05.000-0700"
+
+// Issue is a stored issue.
+type Issue struct {
+	ID          string
+	Key         string
+	Project     string
+	Summary     string
+	Description string
+	IssueType   string
+	Status      string
+	Reporter    string
+	Assignee    string
+	Priority    string
+	Labels      []string
+	Created     time.Time
+	Updated     time.Time
+	Comments    []Comment
+	// Parent is the key of the issue this is a subtask of.
+	Parent string
+	// Links are the links from this issue to others. Each also shows on
+	// the other issue, from its end.
+	Links []Link
+}
+
+// Link is an issue link of Type, one of Blocks, Cloners, Duplicate and
+// Relates, from the issue holding it to the issue with key To.
+type Link struct {
+	Type string
+	To   string
+}
+
+// Comment is a comment on an issue.
+type Comment struct {
+	ID      string
+	Body    string
+	Author  string
+	Created time.Time
+}
+
+// Request is a call the server received. Caller is the authenticated user:
+// the Basic auth email, or "oauth:" and the token for a bearer token.
+type Request struct {
+	Method string
+	Path   string
+	Body   []byte
+	Caller string
+}
+
+type failure struct {
+	method, path string
+	status       int
+	times, used  int
+}
+
+// Server is a fake Jira site listening on a local port.
+type Server struct {
+	// URL is the site URL to configure as jira.base_url.
+	URL string
+
+	srv *httptest.Server
+	now func() time.Time
+
+	mu        sync.Mutex
+	projects  map[string]int
+	issues    []*Issue
+	nextID    int
+	nextComID int
+	failures  []*failure
+	requests  []Request
+}
+
+// New starts a site with the given project keys. With none it has "AIT"
+// and "PROJ".
+func New(projects ...string) *Server {
+	if len(projects) == 0 {
+		projects = []string{"AIT", "PROJ"}
+	}
+	s := &Server{projects: make(map[string]int), nextID: 10000, nextComID: 10000, now: time.Now}
+	for _, p := range projects {
+		s.projects[p] = 0
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/serverInfo", s.serverInfo)
+	mux.HandleFunc("GET /rest/api/2/myself", s.authenticated(s.myself))
+	mux.HandleFunc("POST /rest/api/2/issue", s.authenticated(s.createIssue))
+	mux.HandleFunc("GET /rest/api/2/issue/{key}", s.authenticated(s.getIssue))
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/comment", s.authenticated(s.listComments))
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/comment", s.authenticated(s.addComment))
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/transitions", s.authenticated(s.listTransitions))
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/transitions", s.authenticated(s.doTransition))
+	mux.HandleFunc("GET /rest/api/2/search", s.authenticated(s.search))
+	mux.HandleFunc("POST /rest/api/2/search", s.authenticated(s.search))
+	mux.HandleFunc("POST /rest/api/2/jql/parse", s.authenticated(s.parseJQL))
+
+	s.srv = httptest.NewServer(s.record(mux))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// AddProject creates an empty project.
+func (s *Server) AddProject(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[key]; !ok {
+		s.projects[key] = 0
+	}
+}
+
+// Issue returns a copy of the issue with key.
+func (s *Server) Issue(key string) (Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is := s.find(key); is != nil {
+		return s.copyIssue(is), true
+	}
+	return Issue{}, false
+}
+
+// Issues returns copies of every issue, oldest first.
+func (s *Server) Issues() []Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Issue, len(s.issues))
+	for i, is := range s.issues {
+		out[i] = s.copyIssue(is)
+	}
+	return out
+}
+
+// Seed stores an issue as if it had been created through the API and
+// returns its key. Project defaults to the first project in key order and
+// IssueType to Task.
+func (s *Server) Seed(is Issue) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is.Project == "" {
+		for p := range s.projects {
+			if is.Project == "" || p < is.Project {
+				is.Project = p
+			}
+		}
+	}
+	if _, ok := s.projects[is.Project]; !ok {
+		s.projects[is.Project] = 0
+	}
+	if is.IssueType == "" {
+		is.IssueType = "Task"
+	}
+	return s.store(&is).Key
+}
+
+// Fail makes the next times requests matching method and path prefix fail
+// with status. A times of zero fails them until the server is closed.
+func (s *Server) Fail(method, pathPrefix string, status, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure{method: method, path: pathPrefix, status: status, times: times})
+}
+
+// Requests returns every request received so far, including ones that
+// failed authentication or were injected failures.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) copyIssue(is *Issue) Issue {
+	c := *is
+	c.Comments = slices.Clone(is.Comments)
+	c.Labels = slices.Clone(is.Labels)
+	c.Links = slices.Clone(is.Links)
+	return c
+}
+
+// record logs the request and applies injected failures.
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.RequestURI(), Body: body, Caller: caller(r)})
+		var injected int
+		for _, f := range s.failures {
+			if f.method == r.Method && strings.HasPrefix(r.URL.Path, f.path) && (f.times == 0 || f.used < f.times) {
+				f.used++
+				injected = f.status
+				break
+			}
+		}
+		s.mu.Unlock()
+
+		if injected != 0 {
+			writeErrors(w, injected, []string{"fake jira: injected failure"}, nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// caller returns the Basic auth user, or "oauth:" and the token for a bearer
+// token so that each token is a distinct user.
+func caller(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(auth, "Bearer "):
+		return "oauth:" + strings.TrimPrefix(auth, "Bearer ")
+	case strings.HasPrefix(auth, "Basic "):
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+		if err != nil {
+			return ""
+		}
+		user, _, _ := strings.Cut(string(raw), ":")
+		return user
+	}
+	return ""
+}
+
+func (s *Server) authenticated(h func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := caller(r)
+		if user == "" {
+			writeErrors(w, http.StatusUnauthorized, []string{"You are not authenticated. Authentication required to perform this operation."}, nil)
+			return
+		}
+		h(w, r, user)
+	}
+}
+
+func (s *Server) serverInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"baseUrl":        s.URL,
+		"version":        "1001.0.0-SNAPSHOT",
+		"deploymentType": "Cloud",
+		"serverTitle":    "Fake Jira",
+	})
+}
+
+func (s *Server) myself(w http.ResponseWriter, r *http.Request, user string) {
+	writeJSON(w, http.StatusOK, userJSON(user))
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, user string) {
+	var req struct {
+		Fields struct {
+			Project     struct{ Key string }  `json:"project"`
+			Summary     string                `json:"summary"`
+			Description string                `json:"description"`
+			IssueType   struct{ Name string } `json:"issuetype"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, []string{"Unexpected request body: " + err.Error()}, nil)
+		return
+	}
+	f := req.Fields
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fieldErrors := make(map[string]string)
+	if _, ok := s.projects[f.Project.Key]; !ok {
+		fieldErrors["project"] = "valid project is required"
+	}
+	switch {
+	case strings.TrimSpace(f.Summary) == "":
+		fieldErrors["summary"] = "You must specify a summary of the issue."
+	case len([]rune(f.Summary)) > 255:
+		fieldErrors["summary"] = "Summary must be less than 255 characters."
+	case strings.ContainsAny(f.Summary, "\r\n"):
+		fieldErrors["summary"] = "The summary is invalid because it contains newline characters."
+	}
+	if len([]rune(f.Description)) > 32767 {
+		fieldErrors["description"] = "The entered text is too long. It exceeds the allowed limit of 32,767 characters."
+	}
+	if !slices.Contains(issueTypes, f.IssueType.Name) {
+		fieldErrors["issuetype"] = "Specify a valid issue type"
+	}
+	if len(fieldErrors) > 0 {
+		writeErrors(w, http.StatusBadRequest, nil, fieldErrors)
+		return
+	}
+
+	is := s.store(&Issue{
+		Project:     f.Project.Key,
+		Summary:     f.Summary,
+		Description: f.Description,
+		IssueType:   f.IssueType.Name,
+		Reporter:    user,
+	})
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"id":   is.ID,
+		"key":  is.Key,
+		"self": s.URL + "/rest/api/2/issue/" + is.ID,
+	})
+}
+
+// store assigns an ID and key and appends is. Callers hold s.mu.
+func (s *Server) store(is *Issue) *Issue {
+	s.nextID++
+	s.projects[is.Project]++
+	is.ID = strconv.Itoa(s.nextID)
+	is.Key = fmt.Sprintf("%s-%d", is.Project, s.projects[is.Project])
+	if is.Status == "" {
+		is.Status = transitions[0].Status
+	}
+	now := s.now()
+	if is.Created.IsZero() {
+		is.Created = now
+	}
+	if is.Updated.IsZero() {
+		is.Updated = is.Created
+	}
+	s.issues = append(s.issues, is)
+	return is
+}
+
+// find looks an issue up by key or ID. Callers hold s.mu.
+func (s *Server) find(keyOrID string) *Issue {
+	for _, is := range s.issues {
+		if is.Key == keyOrID || is.ID == keyOrID {
+			return is
+		}
+	}
+	return nil
+}
+
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) *Issue {
+	is := s.find(r.PathValue("key"))
+	if is == nil {
+		writeErrors(w, http.StatusNotFound, []string{"Issue does not exist or you do not have permission to see it."}, nil)
+	}
+	return is
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is := s.lookup(w, r); is != nil {
+		var fields []string
+		if f := r.URL.Query().Get("fields"); f != "" {
+			fields = strings.Split(f, ",")
+		}
+		writeJSON(w, http.StatusOK, selectFields(s.issueJSON(is), fields))
+	}
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if is := s.lookup(w, r); is != nil {
+		writeJSON(w, http.StatusOK, commentsJSON(is.Comments))
+	}
+}
+
+func (s *Server) addComment(w http.ResponseWriter, r *http.Request, user string) {
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, []string{"Unexpected request body: " + err.Error()}, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	is := s.lookup(w, r)
+	if is == nil {
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		writeErrors(w, http.StatusBadRequest, nil, map[string]string{"comment": "Comment body can not be empty!"})
+		return
+	}
+	s.nextComID++
+	c := Comment{ID: strconv.Itoa(s.nextComID), Body: req.Body, Author: user, Created: s.now()}
+	is.Comments = append(is.Comments, c)
+	is.Updated = c.Created
+	writeJSON(w, http.StatusCreated, commentJSON(c))
+}
+
+func (s *Server) listTransitions(w http.ResponseWriter, r *http.Request, user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lookup(w, r) == nil {
+		return
+	}
+	var out []map[string]any
+	for _, t := range transitions {
+		out = append(out, map[string]any{
+			"id":   t.ID,
+			"name": t.Name,
+			"to":   map[string]any{"name": t.Status, "statusCategory": map[string]string{"key": t.Category}},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"transitions": out})
+}
+
+func (s *Server) doTransition(w http.ResponseWriter, r *http.Request, user string) {
+	var req struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, []string{"Unexpected request body: " + err.Error()}, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	is := s.lookup(w, r)
+	if is == nil {
+		return
+	}
+	for _, t := range transitions {
+		if t.ID == req.Transition.ID {
+			is.Status = t.Status
+			is.Updated = s.now()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeErrors(w, http.StatusBadRequest, []string{fmt.Sprintf("Transition id '%s' is not valid for this issue.", req.Transition.ID)}, nil)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request, user string) {
+	params := struct {
+		JQL        string   `json:"jql"`
+		StartAt    int      `json:"startAt"`
+		MaxResults int      `json:"maxResults"`
+		Fields     []string `json:"fields"`
+	}{MaxResults: 50}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			writeErrors(w, http.StatusBadRequest, []string{"Unexpected request body: " + err.Error()}, nil)
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		params.JQL = q.Get("jql")
+		if f := q.Get("fields"); f != "" {
+			params.Fields = strings.Split(f, ",")
+		}
+		for name, dst := range map[string]*int{"startAt": &params.StartAt, "maxResults": &params.MaxResults} {
+			if v := q.Get(name); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 0 {
+					writeErrors(w, http.StatusBadRequest, []string{fmt.Sprintf("The value '%s' is invalid for %s.", v, name)}, nil)
+					return
+				}
+				*dst = n
+			}
+		}
+	}
+	params.MaxResults = min(params.MaxResults, 100)
+
+	query, err := parseJQL(params.JQL, user)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, []string{err.Error()}, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []*Issue
+	for _, is := range s.issues {
+		if query.match(is) {
+			matched = append(matched, is)
+		}
+	}
+	query.sort(matched)
+
+	page := []map[string]any{}
+	for i := params.StartAt; i < len(matched) && len(page) < params.MaxResults; i++ {
+		page = append(page, selectFields(s.issueJSON(matched[i]), params.Fields))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"startAt":    params.StartAt,
+		"maxResults": params.MaxResults,
+		"total":      len(matched),
+		"issues":     page,
+	})
+}
+
+// parseJQL checks queries the way Jira's strict JQL validation does,
+// answering with the errors of each.
+func (s *Server) parseJQL(w http.ResponseWriter, r *http.Request, user string) {
+	var req struct {
+		Queries []string `json:"queries"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, []string{"Unexpected request body: " + err.Error()}, nil)
+		return
+	}
+	results := []map[string]any{}
+	for _, jql := range req.Queries {
+		result := map[string]any{"query": jql}
+		if _, err := parseJQL(jql, user); err != nil {
+			result["errors"] = []string{err.Error()}
+		}
+		results = append(results, result)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"queries": results})
+}
+
+// selectFields keeps only the named fields of an issue, as Jira does for a
+// search with a fields list. No names, *all or *navigable keep them all.
+func selectFields(issue map[string]any, names []string) map[string]any {
+	if len(names) == 0 || slices.Contains(names, "*all") || slices.Contains(names, "*navigable") {
+		return issue
+	}
+	all := issue["fields"].(map[string]any)
+	fields := make(map[string]any)
+	for _, name := range names {
+		if v, ok := all[name]; ok {
+			fields[name] = v
+		}
+	}
+	issue["fields"] = fields
+	return issue
+}
+
+// issueJSON renders an issue with all its fields. Callers hold s.mu.
+func (s *Server) issueJSON(is *Issue) map[string]any {
+	fields := s.summaryJSON(is)
+	fields["description"] = is.Description
+	fields["project"] = map[string]string{"key": is.Project, "name": is.Project}
+	fields["reporter"] = userJSON(is.Reporter)
+	fields["assignee"] = userJSON(is.Assignee)
+	fields["labels"] = labels(is.Labels)
+	fields["created"] = is.Created.Format(timeFormat)
+	fields["updated"] = is.Updated.Format(timeFormat)
+	fields["comment"] = commentsJSON(is.Comments)
+	fields["issuelinks"] = s.linksJSON(is)
+	subtasks := []map[string]any{}
+	for _, sub := range s.issues {
+		if sub.Parent == is.Key {
+			subtasks = append(subtasks, s.refJSON(sub))
+		}
+	}
+	fields["subtasks"] = subtasks
+	if parent := s.find(is.Parent); parent != nil {
+		fields["parent"] = s.refJSON(parent)
+	}
+	return map[string]any{
+		"id":     is.ID,
+		"key":    is.Key,
+		"self":   s.URL + "/rest/api/2/issue/" + is.ID,
+		"fields": fields,
+	}
+}
+
+// summaryJSON holds the fields Jira includes for an issue referenced from
+// another one, as a link, subtask or parent.
+func (s *Server) summaryJSON(is *Issue) map[string]any {
+	var category string
+	for _, t := range transitions {
+		if t.Status == is.Status {
+			category = t.Category
+		}
+	}
+	return map[string]any{
+		"summary":   is.Summary,
+		"status":    map[string]any{"name": is.Status, "statusCategory": map[string]string{"key": category}},
+		"priority":  priorityJSON(is.Priority),
+		"issuetype": map[string]string{"name": is.IssueType},
+	}
+}
+
+func (s *Server) refJSON(is *Issue) map[string]any {
+	return map[string]any{
+		"id":     is.ID,
+		"key":    is.Key,
+		"self":   s.URL + "/rest/api/2/issue/" + is.ID,
+		"fields": s.summaryJSON(is),
+	}
+}
+
+// linksJSON renders the links from is and the links to it from other
+// issues. Links to issues that no longer exist are left out.
+func (s *Server) linksJSON(is *Issue) []map[string]any {
+	out := []map[string]any{}
+	link := func(l Link, direction string, other *Issue) {
+		t := linkTypes[l.Type]
+		out = append(out, map[string]any{
+			"id":      strconv.Itoa(len(out) + 1),
+			"type":    map[string]string{"name": l.Type, "inward": t.Inward, "outward": t.Outward},
+			direction: s.refJSON(other),
+		})
+	}
+	for _, l := range is.Links {
+		if to := s.find(l.To); to != nil {
+			link(l, "outwardIssue", to)
+		}
+	}
+	for _, from := range s.issues {
+		for _, l := range from.Links {
+			if l.To == is.Key {
+				link(l, "inwardIssue", from)
+			}
+		}
+	}
+	return out
+}
+
+func priorityJSON(name string) map[string]string {
+	if name == "" {
+		return nil
+	}
+	return map[string]string{"name": name}
+}
+
+func labels(l []string) []string {
+	if l == nil {
+		return []string{}
+	}
+	return l
+}
+
+func commentsJSON(comments []Comment) map[string]any {
+	out := make([]map[string]any, len(comments))
+	for i, c := range comments {
+		out[i] = commentJSON(c)
+	}
+	return map[string]any{"comments": out, "startAt": 0, "maxResults": len(out), "total": len(out)}
+}
+
+func commentJSON(c Comment) map[string]any {
+	return map[string]any{
+		"id":      c.ID,
+		"body":    c.Body,
+		"author":  userJSON(c.Author),
+		"created": c.Created.Format(timeFormat),
+		"updated": c.Created.Format(timeFormat),
+	}
+}
+
+func userJSON(user string) map[string]any {
+	if user == "" {
+		return nil
+	}
+	return map[string]any{"accountId": "fake-" + user, "emailAddress": user, "displayName": user, "active": true}
+}
+
+func writeErrors(w http.ResponseWriter, status int, messages []string, fields map[string]string) {
+	if messages == nil {
+		messages = []string{}
+	}
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	writeJSON(w, status, map[string]any{"errorMessages": messages, "errors": fields})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,172 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func call(t *testing.T, s *Server, method, path string, body any, out any) int {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, s.URL+path, bytes.NewReader(data))
+	req.SetBasicAuth("alice@example.com", "token")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		json.NewDecoder(resp.Body).Decode(out)
+	}
+	return resp.StatusCode
+}
+
+func TestIssueLifecycle(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	var created struct{ Key string }
+	status := call(t, s, "POST", "/rest/api/2/issue", map[string]any{"fields": map[string]any{
+		"project":   map[string]string{"key": "AIT"},
+		"summary":   "Fix login",
+		"issuetype": map[string]string{"name": "Bug"},
+	}}, &created)
+	if status != http.StatusCreated || created.Key != "AIT-1" {
+		t.Fatalf("create: got %d %q", status, created.Key)
+	}
+
+	if status := call(t, s, "POST", "/rest/api/2/issue/AIT-1/comment", map[string]string{"body": "Reproduced on Safari"}, nil); status != http.StatusCreated {
+		t.Fatalf("comment: got %d", status)
+	}
+	if status := call(t, s, "POST", "/rest/api/2/issue/AIT-1/transitions", map[string]any{"transition": map[string]string{"id": "21"}}, nil); status != http.StatusNoContent {
+		t.Fatalf("transition: got %d", status)
+	}
+	if status := call(t, s, "POST", "/rest/api/2/issue/AIT-1/transitions", map[string]any{"transition": map[string]string{"id": "99"}}, nil); status != http.StatusBadRequest {
+		t.Fatalf("invalid transition: got %d", status)
+	}
+
+	is, _ := s.Issue("AIT-1")
+	if is.Status != "In Progress" || is.Reporter != "alice@example.com" || len(is.Comments) != 1 {
+		t.Errorf("got issue %+v", is)
+	}
+	if status := call(t, s, "GET", "/rest/api/2/issue/AIT-2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("missing issue: got %d", status)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := New()
+	defer s.Close()
+	s.Seed(Issue{Project: "AIT", Summary: "Add SSO login", Reporter: "alice@example.com", Assignee: "bob@example.com", Labels: []string{"auth", "sso"}})
+	s.Seed(Issue{Project: "AIT", Summary: "Fix logout crash", IssueType: "Bug", Status: "Done", Reporter: "bob@example.com"})
+	s.Seed(Issue{Project: "PROJ", Summary: "Login page copy", Reporter: "alice@example.com"})
+
+	for _, tc := range []struct {
+		jql  string
+		want []string
+	}{
+		{"", []string{"PROJ-1", "AIT-2", "AIT-1"}},
+		{"project = AIT ORDER BY key ASC", []string{"AIT-1", "AIT-2"}},
+		{"summary ~ login", []string{"PROJ-1", "AIT-1"}},
+		{`status != Done AND reporter = currentUser()`, []string{"PROJ-1", "AIT-1"}},
+		{"issuetype IN (Bug, Epic)", []string{"AIT-2"}},
+		{`project NOT IN ("AIT") AND text ~ "page"`, []string{"PROJ-1"}},
+		{"assignee = bob@example.com", []string{"AIT-1"}},
+		{"labels IN (sso, billing)", []string{"AIT-1"}},
+	} {
+		t.Run(tc.jql, func(t *testing.T) {
+			var res struct {
+				Total  int
+				Issues []struct{ Key string }
+			}
+			if status := call(t, s, "GET", "/rest/api/2/search?jql="+url.QueryEscape(tc.jql), nil, &res); status != http.StatusOK {
+				t.Fatalf("got %d", status)
+			}
+			var keys []string
+			for _, is := range res.Issues {
+				keys = append(keys, is.Key)
+			}
+			if len(keys) != len(tc.want) || res.Total != len(tc.want) {
+				t.Fatalf("got %v (total %d), want %v", keys, res.Total, tc.want)
+			}
+			for i := range keys {
+				if keys[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", keys, tc.want)
+				}
+			}
+		})
+	}
+
+	for _, jql := range []string{"nosuchfield = x", "summary = login", "project = AIT ORDER BY rank", `summary ~ "open`} {
+		if status := call(t, s, "GET", "/rest/api/2/search?jql="+url.QueryEscape(jql), nil, nil); status != http.StatusBadRequest {
+			t.Errorf("%q: got %d, want 400", jql, status)
+		}
+	}
+
+	var page struct {
+		Issues []struct{ Fields map[string]any }
+	}
+	call(t, s, "POST", "/rest/api/2/search", map[string]any{"jql": "key = AIT-1", "fields": []string{"summary", "assignee"}}, &page)
+	if len(page.Issues) != 1 || len(page.Issues[0].Fields) != 2 || page.Issues[0].Fields["summary"] != "Add SSO login" {
+		t.Errorf("search with fields: got %+v", page)
+	}
+}
+
+func TestParseJQL(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	var res struct {
+		Queries []struct {
+			Query  string
+			Errors []string
+		}
+	}
+	status := call(t, s, "POST", "/rest/api/2/jql/parse?validation=strict", map[string]any{"queries": []string{"project = AIT", "summary = login"}}, &res)
+	if status != http.StatusOK || len(res.Queries) != 2 {
+		t.Fatalf("got %d %+v", status, res)
+	}
+	if len(res.Queries[0].Errors) != 0 || len(res.Queries[1].Errors) != 1 {
+		t.Errorf("got %+v, want only the second query rejected", res.Queries)
+	}
+}
+
+func TestLinksAndSubtasks(t *testing.T) {
+	s := New()
+	defer s.Close()
+	s.Seed(Issue{Project: "AIT", Summary: "Add SSO login"})
+	s.Seed(Issue{Project: "AIT", Summary: "Fix session cookie", Links: []Link{{Type: "Blocks", To: "AIT-1"}}})
+	s.Seed(Issue{Project: "AIT", Summary: "Configure IdP", IssueType: "Sub-task", Parent: "AIT-1"})
+
+	var issue struct {
+		Fields struct {
+			IssueLinks []struct {
+				Type         struct{ Inward string }
+				InwardIssue  *struct{ Key string }
+				OutwardIssue *struct{ Key string }
+			}
+			Subtasks []struct{ Key string }
+		}
+	}
+	call(t, s, "GET", "/rest/api/2/issue/AIT-1", nil, &issue)
+	links := issue.Fields.IssueLinks
+	if len(links) != 1 || links[0].InwardIssue == nil || links[0].InwardIssue.Key != "AIT-2" || links[0].Type.Inward != "is blocked by" {
+		t.Errorf("links: got %+v", links)
+	}
+	if sub := issue.Fields.Subtasks; len(sub) != 1 || sub[0].Key != "AIT-3" {
+		t.Errorf("subtasks: got %+v", sub)
+	}
+
+	var fields struct{ Fields map[string]any }
+	call(t, s, "GET", "/rest/api/2/issue/AIT-2?fields=summary,issuelinks", nil, &fields)
+	if len(fields.Fields) != 2 {
+		t.Errorf("get with fields: got %+v", fields)
+	}
+}
@@ -0,0 +1,296 @@
+package jira
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// query is a parsed JQL search. The emulator understands a useful subset:
+// clauses joined by AND on project, key, status, issuetype, reporter,
+// assignee, priority, labels, summary, description and text, with =, !=, ~,
+// IN and NOT IN, followed by an optional ORDER BY on created, updated or key.
+type query struct {
+	clauses []clause
+	orderBy string
+	desc    bool
+}
+
+type clause struct {
+	field  string
+	op     string
+	values []string
+}
+
+// parseJQL parses jql. currentUser() resolves to user.
+func parseJQL(jql, user string) (*query, error) {
+	toks, err := tokenize(jql)
+	if err != nil {
+		return nil, err
+	}
+	q := &query{orderBy: "created", desc: true}
+	p := &parser{toks: toks}
+
+	for !p.done() && !p.keyword("ORDER") {
+		if len(q.clauses) > 0 {
+			if !p.keyword("AND") {
+				return nil, fmt.Errorf("Error in the JQL Query: Expecting 'AND' or 'ORDER BY' but got '%s'.", p.peek())
+			}
+			p.next()
+		}
+		c, err := p.clause(user)
+		if err != nil {
+			return nil, err
+		}
+		q.clauses = append(q.clauses, c)
+	}
+	if p.keyword("ORDER") {
+		p.next()
+		if !p.keyword("BY") {
+			return nil, fmt.Errorf("Error in the JQL Query: Expecting 'BY' after 'ORDER'.")
+		}
+		p.next()
+		field := strings.ToLower(p.next())
+		switch field {
+		case "created", "updated", "key":
+		default:
+			return nil, fmt.Errorf("Not able to sort using field '%s'.", field)
+		}
+		q.orderBy, q.desc = field, false
+		if p.keyword("DESC") || p.keyword("ASC") {
+			q.desc = strings.EqualFold(p.next(), "DESC")
+		}
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("Error in the JQL Query: unexpected '%s'.", p.peek())
+	}
+	return q, nil
+}
+
+func (q *query) match(is *Issue) bool {
+	for _, c := range q.clauses {
+		if !c.match(is) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *query) sort(issues []*Issue) {
+	less := func(a, b *Issue) bool {
+		switch q.orderBy {
+		case "updated":
+			if !a.Updated.Equal(b.Updated) {
+				return a.Updated.Before(b.Updated)
+			}
+		case "created":
+			if !a.Created.Equal(b.Created) {
+				return a.Created.Before(b.Created)
+			}
+		}
+		// Issues created in the same instant keep creation order.
+		return len(a.ID) < len(b.ID) || len(a.ID) == len(b.ID) && a.ID < b.ID
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if q.desc {
+			return less(issues[j], issues[i])
+		}
+		return less(issues[i], issues[j])
+	})
+}
+
+func (c clause) match(is *Issue) bool {
+	var value string
+	switch c.field {
+	case "project":
+		value = is.Project
+	case "key", "issuekey":
+		value = is.Key
+	case "status":
+		value = is.Status
+	case "issuetype", "type":
+		value = is.IssueType
+	case "reporter":
+		value = is.Reporter
+	case "assignee":
+		value = is.Assignee
+	case "priority":
+		value = is.Priority
+	case "summary":
+		value = is.Summary
+	case "description":
+		value = is.Description
+	case "text":
+		value = is.Summary + "\n" + is.Description
+		for _, cm := range is.Comments {
+			value += "\n" + cm.Body
+		}
+	}
+
+	switch c.op {
+	case "~":
+		return containsWords(value, c.values[0])
+	case "!~":
+		return !containsWords(value, c.values[0])
+	}
+	in := false
+	for _, v := range c.values {
+		if c.field == "labels" {
+			in = in || slices.Contains(is.Labels, v)
+		} else if strings.EqualFold(value, v) {
+			in = true
+		}
+	}
+	if c.op == "!=" || c.op == "NOT IN" {
+		return !in
+	}
+	return in
+}
+
+// containsWords reports whether every word of term appears in text, which
+// is roughly how Jira's text search behaves for simple terms.
+func containsWords(text, term string) bool {
+	text = strings.ToLower(text)
+	for _, w := range strings.Fields(strings.ToLower(strings.Trim(term, "*"))) {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+var fields = map[string]bool{
+	"project": true, "key": true, "issuekey": true, "status": true, "issuetype": true,
+	"type": true, "reporter": true, "assignee": true, "priority": true, "labels": true,
+	"summary": true, "description": true, "text": true,
+}
+
+var textFields = map[string]bool{"summary": true, "description": true, "text": true}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) done() bool   { return p.pos >= len(p.toks) }
+func (p *parser) peek() string { return p.toks[min(p.pos, len(p.toks)-1)] }
+
+func (p *parser) next() string {
+	if p.done() {
+		return ""
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	return t
+}
+
+func (p *parser) keyword(k string) bool {
+	return !p.done() && strings.EqualFold(p.toks[p.pos], k)
+}
+
+func (p *parser) clause(user string) (clause, error) {
+	field := strings.ToLower(unquote(p.next()))
+	if !fields[field] {
+		return clause{}, fmt.Errorf("Field '%s' does not exist or you do not have permission to view it.", field)
+	}
+	c := clause{field: field}
+
+	switch op := strings.ToUpper(p.next()); op {
+	case "=", "!=", "~", "!~":
+		c.op = op
+		c.values = []string{p.value(user)}
+	case "IN":
+		c.op = "IN"
+	case "NOT":
+		if !p.keyword("IN") {
+			return clause{}, fmt.Errorf("Error in the JQL Query: Expecting 'IN' after 'NOT'.")
+		}
+		p.next()
+		c.op = "NOT IN"
+	default:
+		return clause{}, fmt.Errorf("Error in the JQL Query: The operator '%s' is not supported.", op)
+	}
+	if c.op == "IN" || c.op == "NOT IN" {
+		if p.next() != "(" {
+			return clause{}, fmt.Errorf("Error in the JQL Query: Expecting '(' after '%s'.", c.op)
+		}
+		for {
+			c.values = append(c.values, p.value(user))
+			sep := p.next()
+			if sep == ")" {
+				break
+			}
+			if sep != "," {
+				return clause{}, fmt.Errorf("Error in the JQL Query: Expecting ',' or ')' but got '%s'.", sep)
+			}
+		}
+	}
+
+	isText := textFields[field]
+	if isText != (c.op == "~" || c.op == "!~") {
+		return clause{}, fmt.Errorf("The operator '%s' is not supported by the '%s' field.", c.op, field)
+	}
+	return c, nil
+}
+
+func (p *parser) value(user string) string {
+	v := p.next()
+	if strings.EqualFold(v, "currentUser") && p.keyword("(") {
+		p.next()
+		p.next() // ")"
+		return user
+	}
+	return unquote(v)
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return strings.ReplaceAll(s[1:len(s)-1], `\`+string(s[0]), string(s[0]))
+	}
+	return s
+}
+
+// tokenize splits jql into words, quoted strings, operators and punctuation.
+func tokenize(jql string) ([]string, error) {
+	var toks []string
+	rs := []rune(jql)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				if rs[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("Error in the JQL Query: unterminated string starting at character %d.", i)
+			}
+			toks = append(toks, string(rs[i:j+1]))
+			i = j + 1
+		case r == '(' || r == ')' || r == ',' || r == '=' || r == '~':
+			toks = append(toks, string(r))
+			i++
+		case r == '!' && i+1 < len(rs) && (rs[i+1] == '=' || rs[i+1] == '~'):
+			toks = append(toks, string(rs[i:i+2]))
+			i += 2
+		default:
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !strings.ContainsRune(`()=,~!"'`, rs[j]) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("Error in the JQL Query: the character '%c' is a reserved JQL character.", r)
+			}
+			toks = append(toks, string(rs[i:j]))
+			i = j
+		}
+	}
+	return toks, nil
+}
@@ -0,0 +1,299 @@
+// Package ollama is a scripted stand-in for the Ollama HTTP API, for tests
+// that need deterministic model output without a running model.
+//
+// Responses are chosen by rules matched against the model name and prompt,
+// in the order they were added. A generate request that no rule matches
+// fails with 500 so an unscripted prompt is noticed rather than answered
+// with something plausible.
+//
+// Embeddings are not scripted: /api/embed returns a bag-of-words vector, so
+// texts that share words are similar and texts that share none are not.
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Rule answers generate requests whose prompt contains every string in
+// Contains and, when Model is set, that name Model.
+type Rule struct {
+	Model    string
+	Contains []string
+	// Response is the generated text. It is streamed in a single chunk.
+	Response string
+	// Status, when not zero or 200, is returned instead of a response, with
+	// Response as the error message.
+	Status int
+	// Delay holds the response back, for timeout and cancellation tests.
+	Delay time.Duration
+	// Times limits how often the rule matches; zero means always.
+	Times int
+
+	used int
+}
+
+// Reply returns a rule that answers prompts containing all of contains.
+func Reply(response string, contains ...string) Rule {
+	return Rule{Contains: contains, Response: response}
+}
+
+// Request is a generate request the server received.
+type Request struct {
+	Model  string
+	Prompt string
+	Stream bool
+}
+
+// EmbedRequest is an embed request the server received.
+type EmbedRequest struct {
+	Model string
+	Input []string
+}
+
+// Server is a fake Ollama listening on a local port.
+type Server struct {
+	// URL is the base URL to configure as ollama.base_url.
+	URL string
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	models   []string
+	rules    []*Rule
+	requests []Request
+	embeds   []EmbedRequest
+}
+
+// New starts a server that reports models as pulled. With no models it
+// reports "llama3" and "nomic-embed-text", the server's defaults.
+func New(models ...string) *Server {
+	if len(models) == 0 {
+		models = []string{"llama3", "nomic-embed-text"}
+	}
+	s := &Server{models: models}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tags", s.tags)
+	mux.HandleFunc("POST /api/generate", s.generate)
+	mux.HandleFunc("POST /api/embed", s.embed)
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Script appends rules. Earlier rules take precedence.
+func (s *Server) Script(rules ...Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range rules {
+		r := rules[i]
+		s.rules = append(s.rules, &r)
+	}
+}
+
+// Requests returns the generate requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// EmbedRequests returns the embed requests received so far.
+func (s *Server) EmbedRequests() []EmbedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]EmbedRequest(nil), s.embeds...)
+}
+
+func (s *Server) tags(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	models := make([]map[string]string, len(s.models))
+	for i, m := range s.models {
+		models[i] = map[string]string{"name": m + ":latest", "model": m + ":latest"}
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"models": models})
+}
+
+func (s *Server) generate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+		Stream *bool  `json:"stream"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	// Ollama streams unless told otherwise.
+	stream := req.Stream == nil || *req.Stream
+
+	rule := s.match(Request{Model: req.Model, Prompt: req.Prompt, Stream: stream})
+	if rule == nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("fake ollama: no rule matches prompt %q", truncate(req.Prompt, 200)),
+		})
+		return
+	}
+	if rule.Delay > 0 {
+		select {
+		case <-time.After(rule.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if rule.Status != 0 && rule.Status != http.StatusOK {
+		writeJSON(w, rule.Status, map[string]string{"error": rule.Response})
+		return
+	}
+
+	words := len(strings.Fields(rule.Response))
+	writeJSON(w, http.StatusOK, map[string]any{
+		"model":             req.Model,
+		"response":          rule.Response,
+		"done":              true,
+		"prompt_eval_count": len(strings.Fields(req.Prompt)),
+		"eval_count":        words,
+	})
+}
+
+func (s *Server) embed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model string          `json:"model"`
+		Input json.RawMessage `json:"input"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	// input is a string or a list of strings.
+	var input []string
+	if err := json.Unmarshal(req.Input, &input); err != nil {
+		var one string
+		if err := json.Unmarshal(req.Input, &one); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "input must be a string or a list of strings"})
+			return
+		}
+		input = []string{one}
+	}
+
+	s.mu.Lock()
+	s.embeds = append(s.embeds, EmbedRequest{Model: req.Model, Input: input})
+	pulled := slices.Contains(s.models, req.Model)
+	s.mu.Unlock()
+	if !pulled {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("model %q not found, try pulling it first", req.Model),
+		})
+		return
+	}
+
+	embeddings := make([][]float64, len(input))
+	tokens := 0
+	for i, text := range input {
+		embeddings[i] = Embed(text)
+		tokens += len(words(text))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"model":             req.Model,
+		"embeddings":        embeddings,
+		"prompt_eval_count": tokens,
+	})
+}
+
+// Dimensions is the length of the vectors returned by Embed.
+const Dimensions = 256
+
+// stopWords are left out of embeddings so that sharing "the" or "to" does
+// not make two texts look related.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "for": true,
+	"on": true, "in": true, "is": true, "are": true, "and": true, "or": true,
+	"with": true, "about": true, "i": true, "me": true, "my": true,
+	"you": true, "it": true, "this": true, "that": true, "please": true,
+}
+
+// Embed returns the fake embedding of text: each word other than a stop word
+// adds one to a dimension picked by its hash, and the vector is normalised.
+// Text without such words embeds to the zero vector.
+func Embed(text string) []float64 {
+	v := make([]float64, Dimensions)
+	for _, w := range words(text) {
+		if stopWords[w] {
+			continue
+		}
+		h := fnv.New32a()
+		h.Write([]byte(w))
+		v[h.Sum32()%Dimensions]++
+	}
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+	return v
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+}
+
+func (s *Server) match(req Request) *Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	for _, r := range s.rules {
+		if r.Times > 0 && r.used >= r.Times {
+			continue
+		}
+		if r.Model != "" && r.Model != req.Model {
+			continue
+		}
+		matched := true
+		for _, c := range r.Contains {
+			if !strings.Contains(req.Prompt, c) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			r.used++
+			return r
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/audit"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/logging"
+)
+
+// auditMu guards auditLog. Writers hold the read lock for the whole append,
+// so UseAuditLog cannot hand back a log that is still being written to.
+var (
+	auditMu  sync.RWMutex
+	auditLog *audit.Log
+)
+
+// UseAuditLog records every subsequent Jira mutation to log and returns the
+// previously installed log, if any. It waits for appends already in flight
+// to finish, so the caller can close the returned log straight away.
+func UseAuditLog(log *audit.Log) *audit.Log {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	previous := auditLog
+	auditLog = log
+	return previous
+}
+
+// recordAudit fills in the request and trace IDs and appends e. A failed
+// write is logged rather than failing a mutation that already happened.
+func recordAudit(ctx context.Context, e audit.Entry) {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+	log := auditLog
+	if log == nil {
+		return
+	}
+	e.RequestID = logging.RequestID(ctx)
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		e.TraceID = sc.TraceID().String()
+	}
+	if err := log.Append(e); err != nil {
+		slog.ErrorContext(ctx, "Failed to write audit entry", "action", e.Action, "issue_key", e.IssueKey, "error", err)
+	}
+}
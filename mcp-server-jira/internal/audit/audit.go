@@ -0,0 +1,274 @@
+// Package audit records every Jira mutation as an append-only JSON lines
+// file so a card can be traced back to the caller and prompt that made it.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Actions recorded in Entry.Action.
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionTransition = "transition"
+	ActionComment    = "comment"
+	// ActionReject records a request refused before reaching Jira.
+	ActionReject = "reject"
+)
+
+// Entry is one audit record. Payload and JiraResponse hold the exact bytes
+// sent to and received from Jira. DescriptionFit is "summarized" or
+// "truncated" when the generated description was over the length limit.
+type Entry struct {
+	Time           time.Time       `json:"time"`
+	RequestID      string          `json:"request_id,omitempty"`
+	TraceID        string          `json:"trace_id,omitempty"`
+	Action         string          `json:"action"`
+	Caller         string          `json:"caller"`
+	Project        string          `json:"project"`
+	Prompt         string          `json:"prompt,omitempty"`
+	Model          string          `json:"model,omitempty"`
+	Template       string          `json:"prompt_template,omitempty"`
+	Language       string          `json:"language,omitempty"`
+	DescriptionFit string          `json:"description_fit,omitempty"`
+	LLMOutput      string          `json:"llm_output,omitempty"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	JiraStatus     int             `json:"jira_status,omitempty"`
+	JiraResponse   string          `json:"jira_response,omitempty"`
+	IssueKey       string          `json:"issue_key,omitempty"`
+	Violations     []string        `json:"violations,omitempty"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// Log appends entries to a file, rotating it to path.1, path.2, ... once it
+// grows past maxSize. Only maxBackups rotated files are kept.
+type Log struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// Open opens or creates the audit file at path. A maxSize of zero disables
+// rotation.
+func Open(path string, maxSize int64, maxBackups int) (*Log, error) {
+	l := &Log{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Path returns the active audit file.
+func (l *Log) Path() string {
+	return l.path
+}
+
+func (l *Log) open() error {
+	if dir := filepath.Dir(l.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("create audit directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Append writes e as a single line and syncs it to disk.
+func (l *Log) Append(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log %s is closed", l.path)
+	}
+	// A failed rotation still records e in the reopened file; the error is
+	// returned so the caller can report it.
+	var rotateErr error
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if rotateErr = l.rotate(); l.file == nil {
+			return rotateErr
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return errors.Join(rotateErr, fmt.Errorf("write audit entry: %w", err))
+	}
+	return errors.Join(rotateErr, l.file.Sync())
+}
+
+// rotate moves the active file to path.1, shifting older backups up, and
+// opens a fresh one. The active path is reopened even when a rename fails,
+// so a failed rotation leaves an oversized file rather than a closed log.
+func (l *Log) rotate() error {
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		err = fmt.Errorf("close audit log: %w", err)
+	} else if err = l.shiftBackups(); err != nil {
+		err = fmt.Errorf("rotate audit log: %w", err)
+	}
+	if openErr := l.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+func (l *Log) shiftBackups() error {
+	if l.maxBackups == 0 {
+		return os.Remove(l.path)
+	}
+	os.Remove(backupName(l.path, l.maxBackups))
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupName(l.path, i), backupName(l.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(l.path, backupName(l.path, 1))
+}
+
+// Close closes the active file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func backupName(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// Filter selects entries in Query. Zero fields match everything.
+type Filter struct {
+	Project string
+	Caller  string
+	Action  string
+	Since   time.Time
+	Until   time.Time
+}
+
+func (f Filter) match(e Entry) bool {
+	switch {
+	case f.Project != "" && !strings.EqualFold(e.Project, f.Project):
+		return false
+	case f.Caller != "" && !strings.Contains(strings.ToLower(e.Caller), strings.ToLower(f.Caller)):
+		return false
+	case f.Action != "" && e.Action != f.Action:
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.Time.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Query reads path and its rotated backups, oldest first, and calls fn for
+// every entry matching f. Returning false from fn stops the scan.
+func Query(path string, f Filter, fn func(Entry) bool) error {
+	files, err := filesOldestFirst(path)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		more, err := scan(name, f, fn)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+	return nil
+}
+
+func filesOldestFirst(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	type backup struct {
+		name string
+		n    int
+	}
+	var backups []backup
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, path+"."))
+		if err == nil && n > 0 {
+			backups = append(backups, backup{m, n})
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].n > backups[j].n })
+
+	files := make([]string, 0, len(backups)+1)
+	for _, b := range backups {
+		files = append(files, b.name)
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func scan(name string, f Filter, fn func(Entry) bool) (bool, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return false, fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return false, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+		if f.match(e) && !fn(e) {
+			return false, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("read %s: %w", name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,190 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func openLog(t *testing.T, maxSize int64, maxBackups int) *Log {
+	t.Helper()
+	l, err := Open(filepath.Join(t.TempDir(), "audit", "audit.jsonl"), maxSize, maxBackups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+// issueKeys returns the IssueKey of every entry in path and its backups,
+// oldest first.
+func issueKeys(t *testing.T, path string, f Filter) []string {
+	t.Helper()
+	var keys []string
+	if err := Query(path, f, func(e Entry) bool {
+		keys = append(keys, e.IssueKey)
+		return true
+	}); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	return keys
+}
+
+func appendN(t *testing.T, l *Log, from, to int) {
+	t.Helper()
+	for i := from; i <= to; i++ {
+		if err := l.Append(Entry{Action: ActionCreate, Project: "AIT", IssueKey: "AIT-" + strconv.Itoa(i)}); err != nil {
+			t.Fatalf("Append %d: %v", i, err)
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAppendRotatesAndPrunesBackups(t *testing.T) {
+	// Each entry is roughly 100 bytes, so every file holds two of them.
+	l := openLog(t, 250, 2)
+	appendN(t, l, 1, 7)
+
+	for _, name := range []string{l.Path(), l.Path() + ".1", l.Path() + ".2"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(l.Path() + ".3"); !os.IsNotExist(err) {
+		t.Errorf("backup beyond max_backups kept: %v", err)
+	}
+	if info, _ := os.Stat(l.Path()); info.Mode().Perm() != 0o600 {
+		t.Errorf("audit file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	want := []string{"AIT-3", "AIT-4", "AIT-5", "AIT-6", "AIT-7"}
+	if got := issueKeys(t, l.Path(), Filter{}); !equal(got, want) {
+		t.Errorf("entries after rotation = %v, want %v", got, want)
+	}
+}
+
+func TestAppendWithoutBackupsTruncates(t *testing.T) {
+	l := openLog(t, 250, 0)
+	appendN(t, l, 1, 5)
+
+	if _, err := os.Stat(l.Path() + ".1"); !os.IsNotExist(err) {
+		t.Errorf("backup written with max_backups 0: %v", err)
+	}
+	if got := issueKeys(t, l.Path(), Filter{}); !equal(got, []string{"AIT-5"}) {
+		t.Errorf("entries = %v, want [AIT-5]", got)
+	}
+}
+
+func TestFailedRotationKeepsLogOpen(t *testing.T) {
+	l := openLog(t, 250, 1)
+	appendN(t, l, 1, 2)
+
+	// A non-empty directory at path.1 makes the rename fail.
+	blocker := l.Path() + ".1"
+	if err := os.MkdirAll(filepath.Join(blocker, "x"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Append(Entry{Action: ActionCreate, IssueKey: "AIT-3"}); err == nil {
+		t.Error("Append reported no error for a failed rotation")
+	}
+	if err := os.RemoveAll(blocker); err != nil {
+		t.Fatal(err)
+	}
+	appendN(t, l, 4, 4)
+
+	want := []string{"AIT-1", "AIT-2", "AIT-3", "AIT-4"}
+	if got := issueKeys(t, l.Path(), Filter{}); !equal(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+}
+
+func TestAppendAfterClose(t *testing.T) {
+	l := openLog(t, 0, 0)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Append(Entry{Action: ActionCreate}); err == nil {
+		t.Error("Append to a closed log succeeded")
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	l := openLog(t, 0, 0)
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: base, Action: ActionCreate, Caller: "basic:alice@example.com", Project: "AIT", IssueKey: "AIT-1"},
+		{Time: base.Add(time.Hour), Action: ActionComment, Caller: "basic:bob@example.com", Project: "AIT", IssueKey: "AIT-1"},
+		{Time: base.Add(2 * time.Hour), Action: ActionCreate, Caller: "oauth:5b10ac8d", Project: "OPS", IssueKey: "OPS-7"},
+		{Time: base.Add(3 * time.Hour), Action: ActionReject, Caller: "basic:Alice@example.com", Project: "ait"},
+	}
+	for _, e := range entries {
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"all", Filter{}, 4},
+		{"project ignores case", Filter{Project: "AIT"}, 3},
+		{"caller substring ignores case", Filter{Caller: "ALICE"}, 2},
+		{"action", Filter{Action: ActionCreate}, 2},
+		{"since inclusive", Filter{Since: base.Add(time.Hour)}, 3},
+		{"until exclusive", Filter{Until: base.Add(2 * time.Hour)}, 2},
+		{"combined", Filter{Project: "AIT", Action: ActionCreate, Caller: "alice"}, 1},
+		{"no match", Filter{Project: "NOPE"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(issueKeys(t, l.Path(), tt.filter)); got != tt.want {
+				t.Errorf("matched %d entries, want %d", got, tt.want)
+			}
+		})
+	}
+
+	var seen int
+	if err := Query(l.Path(), Filter{}, func(Entry) bool {
+		seen++
+		return seen < 2
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if seen != 2 {
+		t.Errorf("Query kept scanning after fn returned false: %d calls", seen)
+	}
+}
+
+func TestQueryMissingFile(t *testing.T) {
+	if got := issueKeys(t, filepath.Join(t.TempDir(), "none.jsonl"), Filter{}); len(got) != 0 {
+		t.Errorf("entries = %v, want none", got)
+	}
+}
+
+func TestQueryCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte("{\"action\":\"create\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Query(path, Filter{}, func(Entry) bool { return true }); err == nil {
+		t.Error("Query accepted a corrupt line")
+	}
+}
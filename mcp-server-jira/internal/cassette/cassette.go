@@ -0,0 +1,118 @@
+// Package cassette records the outbound HTTP exchanges of an RPC to a file
+// and plays them back later without touching the network, so a request seen
+// in production can be rerun locally against the same LLM output and Jira
+// responses.
+//
+// Recording and playback are scoped by context: a transport wrapped with
+// Wrap records into the Recorder in the request context, answers from the
+// Player in the context, and otherwise passes the request through.
+// Secrets are scrubbed before an exchange is stored; responses keep
+// everything else so a replay returns them as recorded.
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Version is the cassette file format version.
+const Version = 1
+
+// Cassette is one recorded RPC and the HTTP exchanges it caused.
+type Cassette struct {
+	Version    int       `json:"version"`
+	RecordedAt time.Time `json:"recorded_at"`
+	RequestID  string    `json:"request_id,omitempty"`
+	// RPC is the full gRPC method name, such as /jira.JiraService/CreateCard.
+	RPC string `json:"rpc"`
+	// Metadata is the scrubbed incoming metadata the RPC's credentials were
+	// resolved from.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Request is the RPC request in protobuf JSON form.
+	Request json.RawMessage `json:"request"`
+	// Settings holds configuration values by config file key, such as
+	// jira.base_url, that a replay restores so it calls the same URLs with
+	// the same project and models.
+	Settings     map[string]string `json:"settings,omitempty"`
+	Outcome      Outcome           `json:"outcome"`
+	Interactions []Interaction     `json:"interactions"`
+}
+
+// Outcome is what the RPC returned: a response, or a gRPC status code and
+// message.
+type Outcome struct {
+	Code     string          `json:"code"`
+	Message  string          `json:"message,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Interaction is one HTTP exchange with a dependency. Error is set instead
+// of Response when the request failed without a response.
+type Interaction struct {
+	Dependency string        `json:"dependency"`
+	Request    Request       `json:"request"`
+	Response   *Response     `json:"response,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration_ns"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string              `json:"method"`
+	URL    string              `json:"url"`
+	Header map[string][]string `json:"header,omitempty"`
+	Body   string              `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       string              `json:"body,omitempty"`
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("cassette %s has format version %d, want %d", path, c.Version, Version)
+	}
+	return &c, nil
+}
+
+// Save writes c into dir as <time>-<method>-<request id>.json and returns
+// the path. Cassettes hold prompts and Jira content, so the file is only
+// readable by its owner.
+func (c *Cassette) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	name := c.RecordedAt.UTC().Format("20060102T150405.000Z")
+	if i := strings.LastIndex(c.RPC, "/"); i >= 0 {
+		name += "-" + c.RPC[i+1:]
+	}
+	if c.RequestID != "" {
+		name += "-" + c.RequestID
+	}
+	path := filepath.Join(dir, name+".json")
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return path, nil
+}
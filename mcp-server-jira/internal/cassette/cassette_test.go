@@ -0,0 +1,164 @@
+package cassette
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/logging"
+)
+
+const (
+	token        = "ATATT3xFfGF0secret"
+	responseBody = `{"key":"AIT-1","fields":{"reporter":{"emailAddress":"jane@example.com"}},"self":"` + token + `"}`
+	requestBody  = `{"fields":{"summary":"Ask jane@example.com about SSO"}}`
+)
+
+func redactor(t *testing.T) *logging.Redactor {
+	t.Helper()
+	r, err := logging.NewRedactor(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func send(t *testing.T, ctx context.Context, hc *http.Client, url, body string) (*http.Response, string, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/rest/api/2/issue", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Basic Ym90OnNlY3JldA==")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data), nil
+}
+
+// record sends one request through a recording transport and returns the
+// cassette saved for it and the server's URL.
+func record(t *testing.T) (*Cassette, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Api-Token", token)
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, responseBody)
+	}))
+	t.Cleanup(srv.Close)
+
+	r := redactor(t)
+	recorder := NewRecorder(r.String, r.Credentials)
+	hc := Wrap("jira", srv.Client())
+	resp, body, err := send(t, WithRecorder(context.Background(), recorder), hc, srv.URL, requestBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusCreated || body != responseBody {
+		t.Fatalf("recording changed the live response: %d %s", resp.StatusCode, body)
+	}
+
+	c := &Cassette{
+		Version:      Version,
+		RecordedAt:   time.Date(2026, 10, 19, 10, 15, 0, 0, time.UTC),
+		RPC:          "/jira.JiraService/CreateCard",
+		Request:      []byte(`{}`),
+		Interactions: recorder.Interactions(),
+	}
+	path, err := c.Save(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{token, "Ym90OnNlY3JldA=="} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette file contains %q", secret)
+		}
+	}
+	if strings.Contains(string(data), "Ask jane@example.com") {
+		t.Error("cassette file contains the email address from the request body")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded, srv.URL
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	c, url := record(t)
+	if len(c.Interactions) != 1 {
+		t.Fatalf("recorded %d interactions, want 1", len(c.Interactions))
+	}
+
+	player := NewPlayer(c, redactor(t).String, true)
+	// The server is never contacted: replay works against a dead transport.
+	hc := Wrap("jira", &http.Client{Transport: failingTransport{t}})
+	resp, body, err := send(t, WithPlayer(context.Background(), player), hc, url, requestBody)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	want := strings.Replace(responseBody, token, Mask, 1)
+	if resp.StatusCode != http.StatusCreated || body != want {
+		t.Errorf("replayed %d %s, want %d %s", resp.StatusCode, body, http.StatusCreated, want)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("replayed Content-Type %q", got)
+	}
+	if got := resp.Header.Get("X-Api-Token"); got != Mask {
+		t.Errorf("replayed X-Api-Token %q, want it masked", got)
+	}
+	if m := player.Mismatches(); len(m) != 0 {
+		t.Errorf("mismatches: %v", m)
+	}
+	if u := player.Unused(); len(u) != 0 {
+		t.Errorf("unused: %v", u)
+	}
+
+	if _, _, err := send(t, WithPlayer(context.Background(), player), hc, url, requestBody); err == nil {
+		t.Error("a recorded exchange was answered twice")
+	}
+}
+
+func TestReplayBodyMismatch(t *testing.T) {
+	c, url := record(t)
+	hc := Wrap("jira", &http.Client{Transport: failingTransport{t}})
+	changed := `{"fields":{"summary":"Something else"}}`
+
+	lenient := NewPlayer(c, redactor(t).String, false)
+	if _, _, err := send(t, WithPlayer(context.Background(), lenient), hc, url, changed); err != nil {
+		t.Errorf("non-strict replay: %v", err)
+	}
+	if len(lenient.Mismatches()) != 1 {
+		t.Errorf("mismatches = %v, want one", lenient.Mismatches())
+	}
+
+	strict := NewPlayer(c, redactor(t).String, true)
+	if _, _, err := send(t, WithPlayer(context.Background(), strict), hc, url, changed); err == nil {
+		t.Error("strict replay answered a request whose body differs")
+	}
+}
+
+type failingTransport struct{ t *testing.T }
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("replay sent %s %s to the network", req.Method, req.URL)
+	return nil, http.ErrHandlerTimeout
+}
@@ -0,0 +1,274 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mask replaces the value of a sensitive header.
+const Mask = "[REDACTED]"
+
+// sensitiveHeaders are always masked; any header whose name contains one of
+// sensitiveHeaderWords is too.
+var (
+	sensitiveHeaders     = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	sensitiveHeaderWords = []string{"token", "secret", "password", "api-key", "apikey"}
+)
+
+// Scrubber masks secrets and personal data in recorded text.
+type Scrubber func(string) string
+
+type recorderKey struct{}
+type playerKey struct{}
+
+// Recorder collects the exchanges made while serving one RPC.
+type Recorder struct {
+	scrub         Scrubber
+	scrubResponse Scrubber
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a recorder that passes every request URL, header and
+// body through scrub, and every response header and body through
+// scrubResponse, before storing it. Replay hands back the stored response,
+// so scrubResponse should mask only what must never be written to disk,
+// such as credentials; anything else it masks is lost to the replay.
+func NewRecorder(scrub, scrubResponse Scrubber) *Recorder {
+	return &Recorder{scrub: scrub, scrubResponse: scrubResponse}
+}
+
+// WithRecorder returns a context whose outbound requests are recorded by r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// Interactions returns the exchanges recorded so far, in the order they
+// completed.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+func (r *Recorder) add(i Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, i)
+}
+
+// Player answers outbound requests from a cassette instead of the network.
+// Each recorded exchange is used once, in order, matched on dependency,
+// method and URL. A request whose body differs from the recording is still
+// answered, and the difference is reported by Mismatches; in strict mode it
+// fails instead.
+type Player struct {
+	scrub  Scrubber
+	strict bool
+
+	mu         sync.Mutex
+	pending    []Interaction
+	mismatches []string
+}
+
+// NewPlayer returns a player for c. scrub must be the scrubber the
+// cassette was recorded with, so live requests compare equal to it.
+func NewPlayer(c *Cassette, scrub Scrubber, strict bool) *Player {
+	return &Player{scrub: scrub, strict: strict, pending: append([]Interaction(nil), c.Interactions...)}
+}
+
+// WithPlayer returns a context whose outbound requests are answered by p.
+func WithPlayer(ctx context.Context, p *Player) context.Context {
+	return context.WithValue(ctx, playerKey{}, p)
+}
+
+// Mismatches describes requests that were answered although their body
+// differed from the recording, and requests that had no recording.
+func (p *Player) Mismatches() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.mismatches...)
+}
+
+// Unused returns the recorded exchanges the replay never asked for.
+func (p *Player) Unused() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Interaction(nil), p.pending...)
+}
+
+func (p *Player) play(dependency string, req *http.Request, body []byte) (*http.Response, error) {
+	method, url := req.Method, p.scrub(req.URL.String())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for n, i := range p.pending {
+		if i.Dependency != dependency || i.Request.Method != method || i.Request.URL != url {
+			continue
+		}
+		if got := p.scrub(string(body)); got != i.Request.Body {
+			msg := fmt.Sprintf("%s %s %s: request body differs from the recording", dependency, method, url)
+			p.mismatches = append(p.mismatches, msg)
+			if p.strict {
+				return nil, errors.New("cassette: " + msg)
+			}
+		}
+		p.pending = append(p.pending[:n:n], p.pending[n+1:]...)
+
+		if i.Response == nil {
+			return nil, errors.New(i.Error)
+		}
+		resp := &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header(i.Response.Header).Clone(),
+			Body:          io.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}
+		if resp.Header == nil {
+			resp.Header = http.Header{}
+		}
+		return resp, nil
+	}
+
+	msg := fmt.Sprintf("%s %s %s: no recorded exchange left", dependency, method, url)
+	p.mismatches = append(p.mismatches, msg)
+	return nil, errors.New("cassette: " + msg)
+}
+
+// Wrap returns a copy of hc whose transport records and replays requests to
+// dependency as described in the package comment.
+func Wrap(dependency string, hc *http.Client) *http.Client {
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	out := *hc
+	out.Transport = &transport{dependency: dependency, next: next}
+	return &out
+}
+
+type transport struct {
+	dependency string
+	next       http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	player, _ := ctx.Value(playerKey{}).(*Player)
+	recorder, _ := ctx.Value(recorderKey{}).(*Recorder)
+	if player == nil && recorder == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if player != nil {
+		return player.play(t.dependency, req, body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	i := Interaction{
+		Dependency: t.dependency,
+		Request: Request{
+			Method: req.Method,
+			URL:    recorder.scrub(req.URL.String()),
+			Header: scrubHeader(req.Header, recorder.scrub),
+			Body:   recorder.scrub(string(body)),
+		},
+	}
+	if err != nil {
+		i.Error, i.Duration = err.Error(), time.Since(start)
+		recorder.add(i)
+		return nil, err
+	}
+
+	// The response is buffered so it can be stored; a streamed answer
+	// reaches the caller in one piece while recording.
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	i.Duration = time.Since(start)
+	if err != nil {
+		i.Error = err.Error()
+		recorder.add(i)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	i.Response = &Response{
+		StatusCode: resp.StatusCode,
+		Header:     scrubHeader(resp.Header, recorder.scrubResponse),
+		Body:       recorder.scrubResponse(string(respBody)),
+	}
+	recorder.add(i)
+	return resp, nil
+}
+
+// readBody returns the request body and leaves req with an unread copy.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func scrubHeader(h http.Header, scrub Scrubber) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string][]string, len(h))
+	for name, values := range h {
+		masked := make([]string, len(values))
+		for n, v := range values {
+			if sensitiveHeader(name) {
+				masked[n] = Mask
+			} else {
+				masked[n] = scrub(v)
+			}
+		}
+		out[name] = masked
+	}
+	return out
+}
+
+func sensitiveHeader(name string) bool {
+	for _, h := range sensitiveHeaders {
+		if strings.EqualFold(name, h) {
+			return true
+		}
+	}
+	lower := strings.ToLower(name)
+	for _, w := range sensitiveHeaderWords {
+		if strings.Contains(lower, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether outbound requests made with ctx are recorded or
+// replayed. Callers that cache the results of outbound calls should bypass
+// the cache then, so the cassette holds every exchange a replay needs.
+func Active(ctx context.Context) bool {
+	return ctx.Value(playerKey{}) != nil || ctx.Value(recorderKey{}) != nil
+}
@@ -0,0 +1,591 @@
+// Package config loads mcp-server-jira settings from a YAML file, environment
+// variables and command-line flags, in increasing order of precedence.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/lang"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/secrets"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/validate"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the config file when --config is not given.
+const FileEnv = "MCP_SERVER_JIRA_CONFIG"
+
+type Config struct {
+	Server  ServerConfig  `yaml:"server"`
+	Ollama  OllamaConfig  `yaml:"ollama"`
+	Jira    JiraConfig    `yaml:"jira"`
+	Limits  LimitsConfig  `yaml:"limits"`
+	TLS     TLSConfig     `yaml:"tls"`
+	Secrets SecretsConfig `yaml:"secrets"`
+	Health  HealthConfig  `yaml:"health"`
+	Metrics MetricsConfig `yaml:"metrics"`
+	Tracing TracingConfig `yaml:"tracing"`
+	Logging LoggingConfig `yaml:"logging"`
+	Audit   AuditConfig   `yaml:"audit"`
+	Guard   GuardConfig   `yaml:"guard"`
+	Prompts PromptsConfig `yaml:"prompts"`
+	// Router classifies chat messages into intents.
+	Router RouterConfig `yaml:"router"`
+	// Language selects the language generated issues are written in.
+	Language LanguageConfig `yaml:"language"`
+	// Cassettes records each RPC's outbound HTTP exchanges for replay.
+	Cassettes CassettesConfig `yaml:"cassettes"`
+	// Idempotency controls replay of CreateCard requests by idempotency key.
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	// Resilience applies to outbound Jira and Ollama calls.
+	Resilience ResilienceConfig `yaml:"resilience"`
+}
+
+type ServerConfig struct {
+	Addr            string        `yaml:"addr"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type OllamaConfig struct {
+	BaseURL      string `yaml:"base_url"`
+	Model        string `yaml:"model"`
+	RoutingModel string `yaml:"routing_model"`
+	// EmbeddingModel embeds messages and intent examples for the router.
+	EmbeddingModel string        `yaml:"embedding_model"`
+	Timeout        time.Duration `yaml:"timeout"`
+}
+
+type JiraConfig struct {
+	BaseURL             string `yaml:"base_url"`
+	Email               string `yaml:"email"`
+	APIToken            string `yaml:"api_token"`
+	CloudID             string `yaml:"cloud_id"`
+	ProjectKey          string `yaml:"project_key"`
+	AllowServiceAccount bool   `yaml:"allow_service_account"`
+}
+
+type LimitsConfig struct {
+	TitleMaxLength       int `yaml:"title_max_length"`
+	DescriptionMaxLength int `yaml:"description_max_length"`
+	// PromptMaxLength is the longest prompt accepted by CreateCard and
+	// Message, in characters.
+	PromptMaxLength int `yaml:"prompt_max_length"`
+	// SummarizeOverflow asks the model to shorten a description that is
+	// over DescriptionMaxLength instead of cutting it off.
+	SummarizeOverflow bool `yaml:"summarize_overflow"`
+}
+
+// TLSConfig holds the server's certificate and, for the health command, the
+// client side: the CA that verifies the server and the certificate presented
+// when the server requires mutual TLS.
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
+
+	CAFile         string `yaml:"ca_file"`
+	ClientCertFile string `yaml:"client_cert_file"`
+	ClientKeyFile  string `yaml:"client_key_file"`
+	ServerName     string `yaml:"server_name"`
+}
+
+type HealthConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+// MetricsConfig controls the Prometheus endpoint; an empty Addr disables it.
+type MetricsConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+// TracingConfig selects the OpenTelemetry exporter: "none", "otlp" or "file".
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	File        string  `yaml:"file"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// LoggingConfig controls the slog output. RedactPatterns are regular
+// expressions masked in every log line in addition to the built-in rules.
+type LoggingConfig struct {
+	Level          string   `yaml:"level"`
+	Format         string   `yaml:"format"`
+	RedactPatterns []string `yaml:"redact_patterns"`
+}
+
+// AuditConfig controls the Jira mutation audit log; an empty File disables
+// it. The file is rotated once it exceeds MaxSizeMB.
+type AuditConfig struct {
+	File       string `yaml:"file"`
+	MaxSizeMB  int    `yaml:"max_size_mb"`
+	MaxBackups int    `yaml:"max_backups"`
+}
+
+// GuardConfig controls prompt-injection screening of user messages and the
+// policy applied to generated issues. The Jira site's own host is always an
+// allowed link target.
+type GuardConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	LLMClassifier bool     `yaml:"llm_classifier"`
+	AllowedHosts  []string `yaml:"allowed_hosts"`
+}
+
+// PromptsConfig points at a directory of text/template prompts that
+// replace the built-in ones. Empty uses the built-in prompts only.
+type PromptsConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+// RouterConfig controls intent classification of Message prompts.
+// IntentsFile replaces the built-in intents and example utterances; empty
+// uses the built-in set. A message whose best intent scores below
+// MinConfidence, or within MinMargin of the runner-up, gets a clarifying
+// question instead of an action.
+type RouterConfig struct {
+	IntentsFile   string  `yaml:"intents_file"`
+	MinConfidence float64 `yaml:"min_confidence"`
+	MinMargin     float64 `yaml:"min_margin"`
+}
+
+// LanguageConfig selects the language of generated titles and descriptions:
+// the request's language if it names one, else Projects[key], else Default.
+// "auto" writes in the language detected in the prompt. With Bilingual, a
+// description is also given in the prompt's language when that differs.
+type LanguageConfig struct {
+	Default   string            `yaml:"default"`
+	Projects  map[string]string `yaml:"projects"`
+	Bilingual bool              `yaml:"bilingual"`
+}
+
+// CassettesConfig enables recording of every RPC's Jira and Ollama
+// exchanges, scrubbed of secrets, to one file per RPC in Dir. An empty Dir
+// disables recording.
+type CassettesConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+// IdempotencyConfig sets how long a CreateCard result is remembered for its
+// idempotency key.
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// ResilienceConfig controls retries of idempotent outbound calls and the
+// per-dependency circuit breaker. BreakerFailures of 0 disables the breaker.
+type ResilienceConfig struct {
+	MaxAttempts     int           `yaml:"max_attempts"`
+	InitialBackoff  time.Duration `yaml:"initial_backoff"`
+	MaxBackoff      time.Duration `yaml:"max_backoff"`
+	BreakerFailures int           `yaml:"breaker_failures"`
+	BreakerCooldown time.Duration `yaml:"breaker_cooldown"`
+}
+
+type SecretsConfig struct {
+	File    string `yaml:"file"`
+	KeyFile string `yaml:"key_file"`
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:            ":50051",
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Ollama: OllamaConfig{
+			BaseURL:        "http://localhost:11434",
+			Model:          "llama3",
+			RoutingModel:   "llama3",
+			EmbeddingModel: "nomic-embed-text",
+			Timeout:        120 * time.Second,
+		},
+		Jira: JiraConfig{ProjectKey: "PROJ"},
+		Limits: LimitsConfig{
+			TitleMaxLength:       255,
+			DescriptionMaxLength: 1000,
+			PromptMaxLength:      4000,
+			SummarizeOverflow:    true,
+		},
+		Secrets: SecretsConfig{File: "secrets.enc"},
+		Health: HealthConfig{
+			Interval: 30 * time.Second,
+			Timeout:  5 * time.Second,
+		},
+		Metrics: MetricsConfig{Addr: ":9090"},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			File:        "traces.jsonl",
+			SampleRatio: 1,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Audit: AuditConfig{
+			File:       "audit.jsonl",
+			MaxSizeMB:  100,
+			MaxBackups: 10,
+		},
+		Guard:    GuardConfig{Enabled: true},
+		Language: LanguageConfig{Default: lang.Auto},
+		Router: RouterConfig{
+			MinConfidence: 0.5,
+			MinMargin:     0.02,
+		},
+		Idempotency: IdempotencyConfig{TTL: 24 * time.Hour},
+		Resilience: ResilienceConfig{
+			MaxAttempts:     3,
+			InitialBackoff:  200 * time.Millisecond,
+			MaxBackoff:      5 * time.Second,
+			BreakerFailures: 5,
+			BreakerCooldown: 30 * time.Second,
+		},
+	}
+}
+
+// setting ties a config field to its environment variable and flag.
+type setting struct {
+	env    string
+	flag   string
+	usage  string
+	field  func(*Config) any
+	secret bool
+}
+
+var settings = []setting{
+	{env: "MCP_SERVER_JIRA_LISTEN_ADDR", flag: "addr", usage: "gRPC listen address", field: func(c *Config) any { return &c.Server.Addr }},
+	{env: "MCP_SHUTDOWN_TIMEOUT", flag: "shutdown-timeout", usage: "How long to drain in-flight RPCs on shutdown", field: func(c *Config) any { return &c.Server.ShutdownTimeout }},
+	{env: "OLLAMA_BASE_URL", flag: "ollama-url", usage: "Ollama base URL", field: func(c *Config) any { return &c.Ollama.BaseURL }},
+	{env: "OLLAMA_MODEL", flag: "model", usage: "Model used to generate issues", field: func(c *Config) any { return &c.Ollama.Model }},
+	{env: "OLLAMA_ROUTING_MODEL", flag: "routing-model", usage: "Model used by the prompt guard's LLM classifier", field: func(c *Config) any { return &c.Ollama.RoutingModel }},
+	{env: "OLLAMA_EMBEDDING_MODEL", flag: "embedding-model", usage: "Model used to embed messages for intent routing", field: func(c *Config) any { return &c.Ollama.EmbeddingModel }},
+	{env: "OLLAMA_TIMEOUT", flag: "ollama-timeout", usage: "Timeout for issue generation", field: func(c *Config) any { return &c.Ollama.Timeout }},
+	{env: "JIRA_BASE_URL", flag: "jira-url", usage: "Jira site URL", field: func(c *Config) any { return &c.Jira.BaseURL }},
+	{env: "JIRA_EMAIL", flag: "jira-email", usage: "Service account email", field: func(c *Config) any { return &c.Jira.Email }},
+	{env: "JIRA_API_TOKEN", field: func(c *Config) any { return &c.Jira.APIToken }, secret: true},
+	{env: "JIRA_CLOUD_ID", flag: "jira-cloud-id", usage: "Atlassian cloud ID for OAuth callers", field: func(c *Config) any { return &c.Jira.CloudID }},
+	{env: "JIRA_PROJECT_KEY", flag: "project-key", usage: "Default project for message-created issues", field: func(c *Config) any { return &c.Jira.ProjectKey }},
+	{env: "JIRA_ALLOW_SERVICE_ACCOUNT", flag: "allow-service-account", usage: "Fall back to the service account when callers send no credentials", field: func(c *Config) any { return &c.Jira.AllowServiceAccount }},
+	{env: "MCP_TITLE_MAX_LENGTH", flag: "title-max-length", usage: "Maximum issue title length in characters", field: func(c *Config) any { return &c.Limits.TitleMaxLength }},
+	{env: "MCP_PROMPT_MAX_LENGTH", flag: "prompt-max-length", usage: "Maximum prompt length in characters", field: func(c *Config) any { return &c.Limits.PromptMaxLength }},
+	{env: "MCP_DESCRIPTION_MAX_LENGTH", flag: "description-max-length", usage: "Maximum issue description length in characters", field: func(c *Config) any { return &c.Limits.DescriptionMaxLength }},
+	{env: "MCP_SUMMARIZE_OVERFLOW", flag: "summarize-overflow", usage: "Ask the model to shorten over-long descriptions instead of truncating them", field: func(c *Config) any { return &c.Limits.SummarizeOverflow }},
+	{env: "MCP_TLS_CERT_FILE", flag: "tls-cert", usage: "Server TLS certificate", field: func(c *Config) any { return &c.TLS.CertFile }},
+	{env: "MCP_TLS_KEY_FILE", flag: "tls-key", usage: "Server TLS key", field: func(c *Config) any { return &c.TLS.KeyFile }},
+	{env: "MCP_TLS_CLIENT_CA_FILE", flag: "tls-client-ca", usage: "CA for client certificates (enables mutual TLS)", field: func(c *Config) any { return &c.TLS.ClientCAFile }},
+	{env: "MCP_TLS_CA_FILE", flag: "tls-ca", usage: "CA the health command uses to verify the server", field: func(c *Config) any { return &c.TLS.CAFile }},
+	{env: "MCP_TLS_CLIENT_CERT_FILE", flag: "tls-client-cert", usage: "Client certificate the health command presents for mutual TLS", field: func(c *Config) any { return &c.TLS.ClientCertFile }},
+	{env: "MCP_TLS_CLIENT_KEY_FILE", flag: "tls-client-key", usage: "Client key the health command presents for mutual TLS", field: func(c *Config) any { return &c.TLS.ClientKeyFile }},
+	{env: "MCP_TLS_SERVER_NAME", flag: "tls-server-name", usage: "Server name the health command expects in the certificate", field: func(c *Config) any { return &c.TLS.ServerName }},
+	{env: "MCP_HEALTH_INTERVAL", flag: "health-interval", usage: "How often dependencies are probed", field: func(c *Config) any { return &c.Health.Interval }},
+	{env: "MCP_HEALTH_TIMEOUT", flag: "health-timeout", usage: "Timeout for each dependency probe", field: func(c *Config) any { return &c.Health.Timeout }},
+	{env: "MCP_METRICS_ADDR", flag: "metrics-addr", usage: "Prometheus /metrics listen address (empty to disable)", field: func(c *Config) any { return &c.Metrics.Addr }},
+	{env: "MCP_TRACING_EXPORTER", flag: "tracing-exporter", usage: "Trace exporter: none, otlp or file", field: func(c *Config) any { return &c.Tracing.Exporter }},
+	{env: "MCP_TRACING_ENDPOINT", flag: "tracing-endpoint", usage: "OTLP/gRPC collector host:port", field: func(c *Config) any { return &c.Tracing.Endpoint }},
+	{env: "MCP_TRACING_INSECURE", flag: "tracing-insecure", usage: "Connect to the OTLP collector without TLS", field: func(c *Config) any { return &c.Tracing.Insecure }},
+	{env: "MCP_TRACING_FILE", flag: "tracing-file", usage: "File written by the file exporter", field: func(c *Config) any { return &c.Tracing.File }},
+	{env: "MCP_TRACING_SAMPLE_RATIO", flag: "tracing-sample-ratio", usage: "Fraction of new traces to sample", field: func(c *Config) any { return &c.Tracing.SampleRatio }},
+	{env: "MCP_LOG_LEVEL", flag: "log-level", usage: "Log level: debug, info, warn or error", field: func(c *Config) any { return &c.Logging.Level }},
+	{env: "MCP_LOG_FORMAT", flag: "log-format", usage: "Log format: json or text", field: func(c *Config) any { return &c.Logging.Format }},
+	{env: "MCP_AUDIT_FILE", flag: "audit-file", usage: "Audit log of Jira mutations (empty to disable)", field: func(c *Config) any { return &c.Audit.File }},
+	{env: "MCP_AUDIT_MAX_SIZE_MB", flag: "audit-max-size-mb", usage: "Rotate the audit log after this many megabytes", field: func(c *Config) any { return &c.Audit.MaxSizeMB }},
+	{env: "MCP_AUDIT_MAX_BACKUPS", flag: "audit-max-backups", usage: "Rotated audit logs to keep", field: func(c *Config) any { return &c.Audit.MaxBackups }},
+	{env: "MCP_GUARD_ENABLED", flag: "guard", usage: "Screen prompts for injection and enforce output policies", field: func(c *Config) any { return &c.Guard.Enabled }},
+	{env: "MCP_GUARD_LLM_CLASSIFIER", flag: "guard-llm-classifier", usage: "Also ask the routing model whether a prompt is an injection attempt", field: func(c *Config) any { return &c.Guard.LLMClassifier }},
+	{env: "MCP_PROMPTS_DIR", flag: "prompts-dir", usage: "Directory of prompt templates overriding the built-in ones", field: func(c *Config) any { return &c.Prompts.Dir }},
+	{env: "MCP_INTENTS_FILE", flag: "intents-file", usage: "YAML file of intents and example utterances replacing the built-in ones", field: func(c *Config) any { return &c.Router.IntentsFile }},
+	{env: "MCP_ROUTER_MIN_CONFIDENCE", flag: "router-min-confidence", usage: "Lowest intent similarity acted on without asking for clarification", field: func(c *Config) any { return &c.Router.MinConfidence }},
+	{env: "MCP_ROUTER_MIN_MARGIN", flag: "router-min-margin", usage: "Lead over the runner-up intent needed to act without asking for clarification", field: func(c *Config) any { return &c.Router.MinMargin }},
+	{env: "MCP_LANGUAGE", flag: "language", usage: "Language of generated issues: a language code, or auto to follow the prompt", field: func(c *Config) any { return &c.Language.Default }},
+	{env: "MCP_BILINGUAL", flag: "bilingual", usage: "Also write descriptions in the prompt's language when it differs from the target", field: func(c *Config) any { return &c.Language.Bilingual }},
+	{env: "MCP_CASSETTE_DIR", flag: "cassette-dir", usage: "Record each RPC's Jira and Ollama exchanges to this directory (empty to disable)", field: func(c *Config) any { return &c.Cassettes.Dir }},
+	{env: "MCP_IDEMPOTENCY_TTL", flag: "idempotency-ttl", usage: "How long CreateCard results are replayed for their idempotency key", field: func(c *Config) any { return &c.Idempotency.TTL }},
+	{env: "MCP_RETRY_MAX_ATTEMPTS", flag: "retry-max-attempts", usage: "Attempts per idempotent Jira/Ollama call (1 disables retries)", field: func(c *Config) any { return &c.Resilience.MaxAttempts }},
+	{env: "MCP_RETRY_INITIAL_BACKOFF", flag: "retry-initial-backoff", usage: "Backoff before the first retry", field: func(c *Config) any { return &c.Resilience.InitialBackoff }},
+	{env: "MCP_RETRY_MAX_BACKOFF", flag: "retry-max-backoff", usage: "Longest wait between retries, including Retry-After", field: func(c *Config) any { return &c.Resilience.MaxBackoff }},
+	{env: "MCP_BREAKER_FAILURES", flag: "breaker-failures", usage: "Consecutive failures that open a dependency's circuit (0 disables)", field: func(c *Config) any { return &c.Resilience.BreakerFailures }},
+	{env: "MCP_BREAKER_COOLDOWN", flag: "breaker-cooldown", usage: "How long an open circuit fails fast before probing", field: func(c *Config) any { return &c.Resilience.BreakerCooldown }},
+	{env: "MCP_SECRETS_FILE", flag: "secrets-file", usage: "Encrypted secret store", field: func(c *Config) any { return &c.Secrets.File }},
+	{env: "MCP_SECRETS_KEY_FILE", flag: "secrets-key-file", usage: "Key file for the secret store", field: func(c *Config) any { return &c.Secrets.KeyFile }},
+}
+
+// BindFlags registers --config and one flag per setting on fs.
+func BindFlags(fs *pflag.FlagSet) {
+	def := Default()
+	fs.String("config", "", "Path to YAML config file (env "+FileEnv+")")
+	for _, s := range settings {
+		if s.flag == "" {
+			continue
+		}
+		usage := s.usage + " (env " + s.env + ")"
+		switch p := s.field(def).(type) {
+		case *string:
+			fs.String(s.flag, *p, usage)
+		case *int:
+			fs.Int(s.flag, *p, usage)
+		case *bool:
+			fs.Bool(s.flag, *p, usage)
+		case *time.Duration:
+			fs.Duration(s.flag, *p, usage)
+		case *float64:
+			fs.Float64(s.flag, *p, usage)
+		}
+	}
+}
+
+// Load builds the effective configuration: defaults, then the file named by
+// --config or MCP_SERVER_JIRA_CONFIG, then environment variables, then any
+// flags explicitly set on fs. fs may be nil. Keys the file does not know are
+// an error.
+func Load(fs *pflag.FlagSet) (*Config, error) {
+	path := os.Getenv(FileEnv)
+	if fs != nil {
+		if p, _ := fs.GetString("config"); p != "" {
+			path = p
+		}
+	}
+
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		// Unknown keys are rejected so a misspelt setting fails loudly
+		// instead of silently keeping its default.
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if fs != nil {
+		if err := cfg.applyFlags(fs); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyEnv() error {
+	for _, s := range settings {
+		value, ok := os.LookupEnv(s.env)
+		if !ok || value == "" {
+			continue
+		}
+		var err error
+		switch p := s.field(c).(type) {
+		case *string:
+			*p = value
+		case *int:
+			*p, err = strconv.Atoi(value)
+		case *bool:
+			*p, err = strconv.ParseBool(value)
+		case *time.Duration:
+			*p, err = time.ParseDuration(value)
+		case *float64:
+			*p, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", s.env, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) applyFlags(fs *pflag.FlagSet) error {
+	for _, s := range settings {
+		if s.flag == "" || !fs.Changed(s.flag) {
+			continue
+		}
+		var err error
+		switch p := s.field(c).(type) {
+		case *string:
+			*p, err = fs.GetString(s.flag)
+		case *int:
+			*p, err = fs.GetInt(s.flag)
+		case *bool:
+			*p, err = fs.GetBool(s.flag)
+		case *time.Duration:
+			*p, err = fs.GetDuration(s.flag)
+		case *float64:
+			*p, err = fs.GetFloat64(s.flag)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid --%s: %w", s.flag, err)
+		}
+	}
+	return nil
+}
+
+// Validate reports every invalid setting.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr is required"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+	if err := validateURL(c.Ollama.BaseURL); err != nil {
+		errs = append(errs, fmt.Errorf("ollama.base_url: %w", err))
+	}
+	if c.Ollama.Model == "" {
+		errs = append(errs, errors.New("ollama.model is required"))
+	}
+	if c.Ollama.RoutingModel == "" {
+		errs = append(errs, errors.New("ollama.routing_model is required"))
+	}
+	if c.Ollama.EmbeddingModel == "" {
+		errs = append(errs, errors.New("ollama.embedding_model is required"))
+	}
+	if c.Ollama.Timeout <= 0 {
+		errs = append(errs, errors.New("ollama.timeout must be positive"))
+	}
+	if c.Jira.BaseURL != "" {
+		if err := validateURL(c.Jira.BaseURL); err != nil {
+			errs = append(errs, fmt.Errorf("jira.base_url: %w", err))
+		}
+	}
+	if c.Jira.AllowServiceAccount {
+		// With a secret store the email is read from the store instead.
+		switch {
+		case c.Jira.BaseURL == "":
+			errs = append(errs, errors.New("jira.allow_service_account requires jira.base_url"))
+		case c.Jira.Email == "" && !secrets.Configured(c.Secrets.KeyFile):
+			errs = append(errs, errors.New("jira.allow_service_account requires jira.email, or JIRA_EMAIL in the secret store"))
+		}
+	}
+	if !validate.IsProjectKey(c.Jira.ProjectKey) {
+		errs = append(errs, fmt.Errorf("jira.project_key %q is not a valid Jira project key", c.Jira.ProjectKey))
+	}
+	if c.Limits.TitleMaxLength <= 0 || c.Limits.TitleMaxLength > 255 {
+		errs = append(errs, errors.New("limits.title_max_length must be between 1 and 255"))
+	}
+	if c.Limits.DescriptionMaxLength <= 0 || c.Limits.DescriptionMaxLength > 32767 {
+		errs = append(errs, errors.New("limits.description_max_length must be between 1 and 32767"))
+	}
+	if c.Limits.PromptMaxLength <= 0 || c.Limits.PromptMaxLength > 100000 {
+		errs = append(errs, errors.New("limits.prompt_max_length must be between 1 and 100000"))
+	}
+	if c.Health.Interval <= 0 || c.Health.Timeout <= 0 {
+		errs = append(errs, errors.New("health.interval and health.timeout must be positive"))
+	}
+	switch c.Tracing.Exporter {
+	case "none", "otlp":
+	case "file":
+		if c.Tracing.File == "" {
+			errs = append(errs, errors.New("tracing.file is required for the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q must be none, otlp or file", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing.sample_ratio must be between 0 and 1"))
+	}
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("logging.level %q must be debug, info, warn or error", c.Logging.Level))
+	}
+	if f := strings.ToLower(c.Logging.Format); f != "json" && f != "text" {
+		errs = append(errs, fmt.Errorf("logging.format %q must be json or text", c.Logging.Format))
+	}
+	for _, p := range c.Logging.RedactPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Errorf("logging.redact_patterns: %w", err))
+		}
+	}
+	for _, h := range c.Guard.AllowedHosts {
+		if h == "" || strings.ContainsAny(h, ":/ ") {
+			errs = append(errs, fmt.Errorf("guard.allowed_hosts: %q must be a bare host name", h))
+		}
+	}
+	if c.Router.MinConfidence < 0 || c.Router.MinConfidence > 1 || c.Router.MinMargin < 0 || c.Router.MinMargin > 1 {
+		errs = append(errs, errors.New("router.min_confidence and router.min_margin must be between 0 and 1"))
+	}
+	if _, ok := lang.Name(c.Language.Default); !ok && c.Language.Default != lang.Auto {
+		errs = append(errs, fmt.Errorf("language.default %q must be auto or one of %s", c.Language.Default, strings.Join(lang.Codes(), ", ")))
+	}
+	for key, code := range c.Language.Projects {
+		if !validate.IsProjectKey(key) {
+			errs = append(errs, fmt.Errorf("language.projects: %q is not a valid Jira project key", key))
+		}
+		if _, ok := lang.Name(code); !ok && code != lang.Auto {
+			errs = append(errs, fmt.Errorf("language.projects.%s: %q must be auto or one of %s", key, code, strings.Join(lang.Codes(), ", ")))
+		}
+	}
+	if c.Idempotency.TTL <= 0 {
+		errs = append(errs, errors.New("idempotency.ttl must be positive"))
+	}
+	if c.Resilience.MaxAttempts < 1 {
+		errs = append(errs, errors.New("resilience.max_attempts must be at least 1"))
+	}
+	if c.Resilience.InitialBackoff < 0 || c.Resilience.MaxBackoff < c.Resilience.InitialBackoff {
+		errs = append(errs, errors.New("resilience.initial_backoff must not be negative or exceed resilience.max_backoff"))
+	}
+	if c.Resilience.BreakerFailures < 0 || c.Resilience.BreakerCooldown <= 0 {
+		errs = append(errs, errors.New("resilience.breaker_failures must not be negative and resilience.breaker_cooldown must be positive"))
+	}
+	if c.Audit.MaxSizeMB < 0 || c.Audit.MaxBackups < 0 {
+		errs = append(errs, errors.New("audit.max_size_mb and audit.max_backups must not be negative"))
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("tls.cert_file and tls.key_file must be set together"))
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		errs = append(errs, errors.New("tls.client_ca_file requires tls.cert_file and tls.key_file"))
+	}
+	if (c.TLS.ClientCertFile == "") != (c.TLS.ClientKeyFile == "") {
+		errs = append(errs, errors.New("tls.client_cert_file and tls.client_key_file must be set together"))
+	}
+	return errors.Join(errs...)
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%q is not an http(s) URL", raw)
+	}
+	return nil
+}
+
+const redacted = "********"
+
+// Redacted returns a copy of c with secret values masked.
+func (c *Config) Redacted() *Config {
+	out := *c
+	for _, s := range settings {
+		if !s.secret {
+			continue
+		}
+		if p, ok := s.field(&out).(*string); ok && *p != "" {
+			*p = redacted
+		}
+	}
+	return &out
+}
+
+// YAML renders c as a config file.
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
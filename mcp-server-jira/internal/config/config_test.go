@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/secrets"
+	"github.com/spf13/pflag"
+)
+
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func flags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+ollama:
+  model: file-model
+  timeout: 30s
+jira:
+  project_key: FILE
+limits:
+  title_max_length: 100
+`)
+	t.Setenv(FileEnv, path)
+	t.Setenv("OLLAMA_MODEL", "env-model")
+	t.Setenv("MCP_TITLE_MAX_LENGTH", "120")
+	t.Setenv("JIRA_PROJECT_KEY", "")
+
+	cfg, err := Load(flags(t, "--model", "flag-model"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, tc := range []struct {
+		name      string
+		got, want any
+	}{
+		{"default under nothing", cfg.Limits.PromptMaxLength, 4000},
+		{"file over default", cfg.Ollama.Timeout, 30 * time.Second},
+		{"file over empty env", cfg.Jira.ProjectKey, "FILE"},
+		{"env over file", cfg.Limits.TitleMaxLength, 120},
+		{"flag over env", cfg.Ollama.Model, "flag-model"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestLoadUnsetFlagKeepsEnv(t *testing.T) {
+	t.Setenv(FileEnv, "")
+	t.Setenv("OLLAMA_MODEL", "env-model")
+
+	// --model has a default of its own; only an explicit flag may win.
+	cfg, err := Load(flags(t))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Ollama.Model != "env-model" {
+		t.Errorf("got model %q, want env-model", cfg.Ollama.Model)
+	}
+}
+
+func TestLoadConfigFlagOverEnv(t *testing.T) {
+	t.Setenv(FileEnv, writeConfig(t, "jira:\n  project_key: ENVFILE\n"))
+	t.Setenv("JIRA_PROJECT_KEY", "")
+	path := writeConfig(t, "jira:\n  project_key: FLAGFILE\n")
+
+	cfg, err := Load(flags(t, "--config", path))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Jira.ProjectKey != "FLAGFILE" {
+		t.Errorf("got project key %q, want the --config file's FLAGFILE", cfg.Jira.ProjectKey)
+	}
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	for _, yaml := range []string{
+		"olama:\n  model: llama3\n",
+		"ollama:\n  modle: llama3\n",
+	} {
+		t.Setenv(FileEnv, writeConfig(t, yaml))
+		_, err := Load(nil)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("%q: got %v, want an unknown field error", yaml, err)
+		}
+	}
+}
+
+func TestLoadInvalidValues(t *testing.T) {
+	t.Setenv(FileEnv, "")
+	t.Setenv("OLLAMA_TIMEOUT", "soon")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "OLLAMA_TIMEOUT") {
+		t.Errorf("invalid env: got %v", err)
+	}
+
+	t.Setenv("OLLAMA_TIMEOUT", "")
+	t.Setenv(FileEnv, writeConfig(t, "ollama:\n  timeout: soon\n"))
+	if _, err := Load(nil); err == nil {
+		t.Error("invalid file value: got no error")
+	}
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	t.Setenv(FileEnv, writeConfig(t, "# nothing set\n"))
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Server.Addr != Default().Server.Addr {
+		t.Errorf("got addr %q, want the default", cfg.Server.Addr)
+	}
+}
+
+func TestExampleConfig(t *testing.T) {
+	t.Setenv(FileEnv, "../../config.example.yaml")
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestValidateServiceAccount(t *testing.T) {
+	t.Setenv(secrets.KeyEnv, "")
+	cfg := Default()
+	cfg.Jira.BaseURL = "https://example.atlassian.net"
+	cfg.Jira.AllowServiceAccount = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jira.email") {
+		t.Errorf("no email and no secret store: got %v", err)
+	}
+
+	// The README's setup: JIRA_EMAIL lives only in the secret store.
+	cfg.Secrets.KeyFile = "secrets.key"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("email in the store via key file: %v", err)
+	}
+	cfg.Secrets.KeyFile = ""
+	t.Setenv(secrets.KeyEnv, "a2V5")
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("email in the store via %s: %v", secrets.KeyEnv, err)
+	}
+
+	cfg.Jira.BaseURL = ""
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jira.base_url") {
+		t.Errorf("no base URL: got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/cassette"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/secrets"
+)
+
+// gRPC metadata keys an authenticated caller uses to act as its own Jira user.
+const (
+	MetadataJiraEmail     = "x-jira-email"
+	MetadataJiraAPIToken  = "x-jira-api-token"
+	MetadataJiraCloudID   = "x-jira-cloud-id"
+	MetadataAuthorization = "authorization"
+)
+
+const atlassianAPIBaseURL = "https://api.atlassian.com/ex/jira/"
+
+var ErrNoCredentials = errors.New("no Jira credentials supplied by caller and service account fallback is disabled")
+
+// CredentialSecrets name the service account credentials. When a secret
+// store is in use they are read from it alone, and they are never loaded
+// from a .env file.
+var CredentialSecrets = []string{"JIRA_EMAIL", "JIRA_API_TOKEN"}
+
+var secretStore atomic.Pointer[secrets.Store]
+
+// UseSecretStore makes service account credentials resolve from the
+// encrypted store instead of the configuration and process environment.
+func UseSecretStore(store *secrets.Store) {
+	secretStore.Store(store)
+}
+
+func lookupSecret(name, fallback string) string {
+	if store := secretStore.Load(); store != nil {
+		value, _ := store.Get(name)
+		return value
+	}
+	return fallback
+}
+
+// Credentials identify the Jira user a request is performed as. Either
+// Email/APIToken (basic auth) or AccessToken (OAuth 2.0 3LO) is set.
+// AccountID is the OAuth user's Atlassian account, set by IdentifyCaller.
+type Credentials struct {
+	BaseURL        string
+	Email          string
+	APIToken       string
+	AccessToken    string
+	CloudID        string
+	AccountID      string
+	ServiceAccount bool
+}
+
+// CredentialsFromContext resolves the Jira credentials for an incoming gRPC
+// request. Credentials in the request metadata win; the service account is
+// used only when jira.allow_service_account is enabled.
+func CredentialsFromContext(ctx context.Context) (*Credentials, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		creds, err := credentialsFromMetadata(md)
+		if err != nil {
+			return nil, err
+		}
+		if creds != nil {
+			return creds, nil
+		}
+	}
+
+	if !settings().Jira.AllowServiceAccount {
+		return nil, ErrNoCredentials
+	}
+	return ServiceAccountCredentials()
+}
+
+// ServiceAccountCredentials returns the shared Jira account. The email and
+// API token are read from the secret store when one is in use.
+func ServiceAccountCredentials() (*Credentials, error) {
+	cfg := settings().Jira
+	creds := &Credentials{
+		BaseURL:        cfg.BaseURL,
+		Email:          lookupSecret("JIRA_EMAIL", cfg.Email),
+		APIToken:       lookupSecret("JIRA_API_TOKEN", cfg.APIToken),
+		ServiceAccount: true,
+	}
+	if creds.BaseURL == "" || creds.Email == "" || creds.APIToken == "" {
+		return nil, fmt.Errorf("missing required Jira service account credentials or URL")
+	}
+	return creds, nil
+}
+
+func credentialsFromMetadata(md metadata.MD) (*Credentials, error) {
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+
+	if auth := first(MetadataAuthorization); auth != "" {
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || token == "" {
+			return nil, fmt.Errorf("unsupported authorization metadata: expected a Bearer token")
+		}
+		cloudID := first(MetadataJiraCloudID)
+		if cloudID == "" {
+			cloudID = settings().Jira.CloudID
+		}
+		if cloudID == "" {
+			return nil, fmt.Errorf("OAuth token supplied without %s metadata or jira.cloud_id", MetadataJiraCloudID)
+		}
+		return &Credentials{
+			BaseURL:     atlassianAPIBaseURL + cloudID,
+			AccessToken: token,
+			CloudID:     cloudID,
+		}, nil
+	}
+
+	email, token := first(MetadataJiraEmail), first(MetadataJiraAPIToken)
+	if email == "" && token == "" {
+		return nil, nil
+	}
+	if email == "" || token == "" {
+		return nil, fmt.Errorf("both %s and %s metadata are required", MetadataJiraEmail, MetadataJiraAPIToken)
+	}
+
+	baseURL := settings().Jira.BaseURL
+	if baseURL == "" {
+		return nil, fmt.Errorf("jira.base_url is not configured")
+	}
+	return &Credentials{
+		BaseURL:  baseURL,
+		Email:    email,
+		APIToken: token,
+	}, nil
+}
+
+// accountTTL is how long an OAuth token's account is remembered when the
+// token does not say when it expires: the lifetime of an Atlassian access
+// token.
+const accountTTL = time.Hour
+
+// accountCache remembers the account behind each OAuth token, keyed by the
+// token's SHA-256, until the token expires.
+type accountCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedAccount
+}
+
+type cachedAccount struct {
+	accountID string
+	expires   time.Time
+}
+
+var oauthAccounts = &accountCache{entries: map[string]cachedAccount{}}
+
+func (a *accountCache) get(key string, now time.Time) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	e, ok := a.entries[key]
+	if !ok || !now.Before(e.expires) {
+		return "", false
+	}
+	return e.accountID, true
+}
+
+// put stores accountID until expires and drops the entries of expired
+// tokens, which are never presented again.
+func (a *accountCache) put(key, accountID string, expires, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for k, e := range a.entries {
+		if !now.Before(e.expires) {
+			delete(a.entries, k)
+		}
+	}
+	if now.Before(expires) {
+		a.entries[key] = cachedAccount{accountID: accountID, expires: expires}
+	}
+}
+
+// tokenExpiry returns when an account looked up for token at now should be
+// forgotten: after accountTTL, or sooner if the token is a JWT, as Atlassian
+// access tokens are, whose exp claim is earlier.
+func tokenExpiry(token string, now time.Time) time.Time {
+	expires := now.Add(accountTTL)
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return expires
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return expires
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return expires
+	}
+	if exp := time.Unix(claims.Exp, 0); exp.Before(expires) {
+		return exp
+	}
+	return expires
+}
+
+// IdentifyCaller sets AccountID for an OAuth caller from Jira's myself
+// resource, so that two users of the same site are told apart. Basic auth
+// callers are already identified by their email.
+func IdentifyCaller(ctx context.Context, c *Credentials) error {
+	if c.AccessToken == "" || c.AccountID != "" {
+		return nil
+	}
+	lookup := func() (string, error) {
+		var me struct {
+			AccountID string `json:"accountId"`
+		}
+		if err := jiraRead(ctx, c, "myself", http.MethodGet, "/rest/api/2/myself", nil, &me); err != nil {
+			return "", err
+		}
+		if me.AccountID == "" {
+			return "", errors.New("jira returned no accountId for the OAuth token")
+		}
+		return me.AccountID, nil
+	}
+
+	sum := sha256.Sum256([]byte(c.AccessToken))
+	key := hex.EncodeToString(sum[:])
+	now := time.Now()
+	// A cassette must hold the lookup too, or a replay in a fresh process
+	// would have nothing to answer it with.
+	if !cassette.Active(ctx) {
+		if accountID, ok := oauthAccounts.get(key, now); ok {
+			c.AccountID = accountID
+			return nil
+		}
+	}
+	accountID, err := lookup()
+	if err != nil {
+		return fmt.Errorf("failed to identify OAuth caller: %w", err)
+	}
+	oauthAccounts.put(key, accountID, tokenExpiry(c.AccessToken, now), now)
+	c.AccountID = accountID
+	return nil
+}
+
+// Authorization returns the value for the outbound Authorization header.
+func (c *Credentials) Authorization() string {
+	if c.AccessToken != "" {
+		return "Bearer " + c.AccessToken
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Email+":"+c.APIToken))
+}
+
+// Caller describes who the request is performed as, for logging, auditing
+// and scoping idempotency keys. OAuth callers must have been identified.
+func (c *Credentials) Caller() string {
+	switch {
+	case c.AccessToken != "":
+		return "oauth:" + c.AccountID + "@" + c.CloudID
+	case c.ServiceAccount:
+		return "service-account:" + c.Email
+	default:
+		return c.Email
+	}
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	fakejira "github.com/cuenobi/mcp-platform/mcp-server-jira/fake/jira"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/cassette"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/config"
+)
+
+func TestIdentifyCaller(t *testing.T) {
+	Configure(config.Default())
+	fake := fakejira.New("AIT")
+	t.Cleanup(fake.Close)
+
+	identify := func(token string) string {
+		t.Helper()
+		creds := &Credentials{BaseURL: fake.URL, AccessToken: token, CloudID: "cloud-1"}
+		if err := IdentifyCaller(context.Background(), creds); err != nil {
+			t.Fatalf("IdentifyCaller(%s): %v", token, err)
+		}
+		return creds.Caller()
+	}
+
+	alice, bob := identify("token-alice"), identify("token-bob")
+	if alice == bob {
+		t.Errorf("two OAuth users share caller %q", alice)
+	}
+	if want := "oauth:fake-oauth:token-alice@cloud-1"; alice != want {
+		t.Errorf("Caller() = %q, want %q", alice, want)
+	}
+	if again := identify("token-alice"); again != alice {
+		t.Errorf("second lookup = %q, want %q", again, alice)
+	}
+
+	myselfCalls := func() int {
+		var n int
+		for _, r := range fake.Requests() {
+			if r.Path == "/rest/api/2/myself" {
+				n++
+			}
+		}
+		return n
+	}
+	if lookups := myselfCalls(); lookups != 2 {
+		t.Errorf("myself called %d times, want once per token", lookups)
+	}
+
+	// While recording, the cached account is looked up again so the
+	// cassette holds the exchange.
+	keep := func(s string) string { return s }
+	recorder := cassette.NewRecorder(keep, keep)
+	recorded := &Credentials{BaseURL: fake.URL, AccessToken: "token-alice", CloudID: "cloud-1"}
+	if err := IdentifyCaller(cassette.WithRecorder(context.Background(), recorder), recorded); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(recorder.Interactions()); n != 1 || recorded.Caller() != alice {
+		t.Errorf("recorded %d lookups as %q, want 1 as %q", n, recorded.Caller(), alice)
+	}
+
+	// The account of an expired token is not remembered.
+	expired := jwt(time.Now().Add(-time.Minute))
+	before := myselfCalls()
+	identify(expired)
+	identify(expired)
+	if n := myselfCalls() - before; n != 2 {
+		t.Errorf("myself called %d times for an expired token, want every time", n)
+	}
+
+	basic := &Credentials{BaseURL: fake.URL, Email: "bot@example.com", APIToken: "t"}
+	if err := IdentifyCaller(context.Background(), basic); err != nil || basic.Caller() != "bot@example.com" {
+		t.Errorf("basic auth caller = %q, %v", basic.Caller(), err)
+	}
+
+	if err := IdentifyCaller(context.Background(), &Credentials{BaseURL: fake.URL + "/missing", AccessToken: "token-carol"}); err == nil {
+		t.Error("IdentifyCaller succeeded without a myself resource")
+	}
+}
+
+// jwt returns an unsigned token whose exp claim is exp.
+func jwt(exp time.Time) string {
+	claims := fmt.Sprintf(`{"sub":"alice","exp":%d}`, exp.Unix())
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".sig"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		token string
+		want  time.Time
+	}{
+		{"opaque token", "token-alice", now.Add(accountTTL)},
+		{"expires sooner", jwt(now.Add(10 * time.Minute)), now.Add(10 * time.Minute)},
+		{"expires later", jwt(now.Add(3 * time.Hour)), now.Add(accountTTL)},
+		{"already expired", jwt(now.Add(-time.Minute)), now.Add(-time.Minute)},
+		{"undecodable claims", "a.!!!.c", now.Add(accountTTL)},
+		{"no exp claim", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"x"}`)) + ".c", now.Add(accountTTL)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenExpiry(tt.token, now); !got.Equal(tt.want) {
+				t.Errorf("tokenExpiry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain is the google.rpc.ErrorInfo domain for errors raised by this
+// server.
+const ErrorDomain = "mcp-server-jira"
+
+// APIError is a non-success response from Jira or Ollama. Messages and
+// FieldErrors carry Jira's errorMessages and errors (or Ollama's error).
+type APIError struct {
+	Dependency  string
+	Operation   string
+	StatusCode  int
+	Status      string
+	Messages    []string
+	FieldErrors map[string]string
+}
+
+// newAPIError parses body as a Jira or Ollama error document, falling back
+// to the raw body when it is neither.
+func newAPIError(dependency, operation string, resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		Dependency: dependency,
+		Operation:  operation,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	var doc struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+		Error         string            `json:"error"`
+	}
+	if json.Unmarshal(body, &doc) == nil {
+		e.Messages = doc.ErrorMessages
+		e.FieldErrors = doc.Errors
+		if doc.Error != "" {
+			e.Messages = append(e.Messages, doc.Error)
+		}
+	}
+	if len(e.Messages) == 0 && len(e.FieldErrors) == 0 {
+		if text := strings.TrimSpace(string(body)); text != "" {
+			e.Messages = []string{text}
+		}
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s failed: %s", e.Dependency, e.Operation, e.Status)
+	if len(e.Messages) > 0 {
+		b.WriteString(": " + strings.Join(e.Messages, "; "))
+	}
+	for _, field := range e.fields() {
+		fmt.Fprintf(&b, "; %s: %s", field, e.FieldErrors[field])
+	}
+	return b.String()
+}
+
+func (e *APIError) fields() []string {
+	fields := make([]string, 0, len(e.FieldErrors))
+	for field := range e.FieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Code maps the HTTP status to the closest gRPC code.
+func (e *APIError) Code() codes.Code {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return codes.InvalidArgument
+	case e.StatusCode == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case e.StatusCode == http.StatusForbidden:
+		return codes.PermissionDenied
+	case e.StatusCode == http.StatusNotFound:
+		return codes.NotFound
+	case e.StatusCode == http.StatusConflict:
+		return codes.Aborted
+	case e.StatusCode == http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case e.StatusCode == http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case e.StatusCode >= 500:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+func (e *APIError) reason() string {
+	var suffix string
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		suffix = "BAD_REQUEST"
+	case http.StatusUnauthorized:
+		suffix = "UNAUTHORIZED"
+	case http.StatusForbidden:
+		suffix = "FORBIDDEN"
+	case http.StatusNotFound:
+		suffix = "NOT_FOUND"
+	case http.StatusConflict:
+		suffix = "CONFLICT"
+	case http.StatusTooManyRequests:
+		suffix = "RATE_LIMITED"
+	default:
+		suffix = "ERROR"
+	}
+	return strings.ToUpper(e.Dependency) + "_" + suffix
+}
+
+// GRPCStatus returns the status sent to clients: an ErrorInfo naming the
+// dependency and HTTP status, and a BadRequest with one violation per field
+// error.
+func (e *APIError) GRPCStatus() *status.Status {
+	message := fmt.Sprintf("%s %s failed: %s", e.Dependency, e.Operation, e.Status)
+	if len(e.Messages) > 0 {
+		message += ": " + strings.Join(e.Messages, "; ")
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: e.reason(),
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"dependency":  e.Dependency,
+			"operation":   e.Operation,
+			"http_status": strconv.Itoa(e.StatusCode),
+		},
+	}}
+	if len(e.FieldErrors) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, field := range e.fields() {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: e.FieldErrors[field],
+			})
+		}
+		details = append(details, br)
+	}
+	return withDetails(status.New(e.Code(), message), details...)
+}
+
+// ErrorStatus builds a status carrying an ErrorInfo with reason and metadata.
+func ErrorStatus(code codes.Code, message, reason string, metadata map[string]string) *status.Status {
+	return withDetails(status.New(code, message), &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+}
+
+// InvalidArgumentStatus builds an InvalidArgument status with a BadRequest
+// violation for field.
+func InvalidArgumentStatus(field, description string) *status.Status {
+	return withDetails(status.New(codes.InvalidArgument, field+": "+description), &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	})
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
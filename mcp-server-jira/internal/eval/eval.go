@@ -0,0 +1,360 @@
+// Package eval scores the intent router and the issue-generation prompt
+// against a dataset of messages with expected outcomes, so prompt, example
+// and model changes can be compared instead of tuned blindly.
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/guard"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/intent"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/lang"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+)
+
+// Reject is the expected intent of a message the prompt guard should
+// refuse.
+const Reject = "reject"
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Dataset is the YAML file of evaluation cases.
+type Dataset struct {
+	Cases []Case `yaml:"cases"`
+}
+
+// Case is one message and what it should produce. Intent is an intent
+// name, "clarify" or "reject"; empty skips the routing check. Card is
+// checked whenever a card is generated, which happens for cases expected to
+// be create_issue or that set Card. Language requests the card in that
+// language, as CreateCardRequest.language does.
+type Case struct {
+	Name     string           `yaml:"name"`
+	Message  string           `yaml:"message"`
+	Project  string           `yaml:"project"`
+	Language string           `yaml:"language"`
+	Intent   string           `yaml:"intent"`
+	Card     *CardExpectation `yaml:"card"`
+}
+
+// CardExpectation lists properties the generated issue must have. Matching
+// is case-insensitive.
+type CardExpectation struct {
+	TitleContains        []string `yaml:"title_contains"`
+	DescriptionContains  []string `yaml:"description_contains"`
+	DescriptionMinLength int      `yaml:"description_min_length"`
+}
+
+// LoadDataset reads and checks a dataset file.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read dataset: %w", err)
+	}
+	var ds Dataset
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&ds); err != nil {
+		return nil, fmt.Errorf("parse dataset %s: %w", path, err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for i, c := range ds.Cases {
+		switch {
+		case c.Name == "":
+			errs = append(errs, fmt.Errorf("case %d: name is required", i+1))
+		case seen[c.Name]:
+			errs = append(errs, fmt.Errorf("case %q: duplicate name", c.Name))
+		}
+		seen[c.Name] = true
+		if strings.TrimSpace(c.Message) == "" {
+			errs = append(errs, fmt.Errorf("case %q: message is required", c.Name))
+		}
+		if _, ok := lang.Name(c.Language); !ok && c.Language != "" {
+			errs = append(errs, fmt.Errorf("case %q: language %q must be one of %s", c.Name, c.Language, strings.Join(lang.Codes(), ", ")))
+		}
+		if c.Intent != "" && !namePattern.MatchString(c.Intent) {
+			errs = append(errs, fmt.Errorf("case %q: intent %q is not an intent name", c.Name, c.Intent))
+		}
+	}
+	if len(ds.Cases) == 0 {
+		errs = append(errs, errors.New("dataset has no cases"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid dataset %s:\n%w", path, err)
+	}
+	return &ds, nil
+}
+
+// Result is the outcome of one case. Intent is "reject" when the prompt
+// guard refused the message.
+type Result struct {
+	Name           string      `json:"name"`
+	Message        string      `json:"message"`
+	ExpectedIntent string      `json:"expected_intent,omitempty"`
+	Intent         string      `json:"intent,omitempty"`
+	Confidence     float64     `json:"confidence"`
+	IntentCorrect  bool        `json:"intent_correct"`
+	Card           *CardResult `json:"card,omitempty"`
+	Error          string      `json:"error,omitempty"`
+	LatencyMS      int64       `json:"latency_ms"`
+}
+
+// CardResult scores a generated issue. Problems explains every failed check.
+type CardResult struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Template        string   `json:"template"`
+	Language        string   `json:"language"`
+	Valid           bool     `json:"valid"`
+	WithinLimits    bool     `json:"within_limits"`
+	ExpectationsMet bool     `json:"expectations_met"`
+	Problems        []string `json:"problems,omitempty"`
+}
+
+// Summary aggregates the results of a run.
+type Summary struct {
+	Cases           int `json:"cases"`
+	Errors          int `json:"errors"`
+	IntentCases     int `json:"intent_cases"`
+	IntentCorrect   int `json:"intent_correct"`
+	Clarifications  int `json:"clarifications"`
+	Cards           int `json:"cards"`
+	ValidCards      int `json:"valid_cards"`
+	CardsInLimits   int `json:"cards_within_limits"`
+	ExpectationsMet int `json:"expectations_met"`
+}
+
+// Report is a complete evaluation run. Templates maps each prompt template
+// to the ID used for the default project; Intents is the intents file, or
+// "builtin".
+type Report struct {
+	Label          string            `json:"label"`
+	Dataset        string            `json:"dataset"`
+	StartedAt      time.Time         `json:"started_at"`
+	Model          string            `json:"model"`
+	EmbeddingModel string            `json:"embedding_model"`
+	Intents        string            `json:"intents"`
+	Templates      map[string]string `json:"templates"`
+	Summary        Summary           `json:"summary"`
+	Results        []Result          `json:"results"`
+}
+
+// Run evaluates every case in ds against the model and prompts currently
+// configured in the server package. Nothing is written to Jira. progress,
+// if not nil, is called after each case.
+func Run(ctx context.Context, ds *Dataset, progress func(Result)) (*Report, error) {
+	cfg := jira.CurrentConfig()
+	intents := jira.CurrentIntents()
+	for _, c := range ds.Cases {
+		if _, ok := intents.Lookup(c.Intent); !ok && c.Intent != "" && c.Intent != intent.Clarify && c.Intent != Reject {
+			return nil, fmt.Errorf("case %q expects intent %q, which %s does not define", c.Name, c.Intent, intents.Source)
+		}
+	}
+	report := &Report{
+		StartedAt:      time.Now().UTC(),
+		Model:          cfg.Ollama.Model,
+		EmbeddingModel: cfg.Ollama.EmbeddingModel,
+		Intents:        intents.Source,
+		Templates:      make(map[string]string),
+	}
+	for _, name := range prompts.Names {
+		if _, id, err := jira.RenderPrompt(name, cfg.Jira.ProjectKey, "", ""); err == nil {
+			report.Templates[name] = id
+		}
+	}
+
+	for _, c := range ds.Cases {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		r := runCase(ctx, c)
+		report.Results = append(report.Results, r)
+		if progress != nil {
+			progress(r)
+		}
+	}
+	report.Summary = summarize(report.Results)
+	return report, nil
+}
+
+func runCase(ctx context.Context, c Case) (r Result) {
+	cfg := jira.CurrentConfig()
+	project := c.Project
+	if project == "" {
+		project = cfg.Jira.ProjectKey
+	}
+	r = Result{Name: c.Name, Message: c.Message, ExpectedIntent: c.Intent}
+	start := time.Now()
+	defer func() { r.LatencyMS = time.Since(start).Milliseconds() }()
+
+	var policyErr *jira.PolicyError
+	err := jira.ScreenPrompt(ctx, nil, project, c.Message)
+	switch {
+	case errors.As(err, &policyErr):
+		r.Intent = Reject
+	case err != nil:
+		r.Error = err.Error()
+		return r
+	default:
+		result, err := jira.ClassifyMessage(ctx, c.Message)
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		r.Intent, r.Confidence = result.Intent, result.Confidence
+	}
+	r.IntentCorrect = c.Intent == "" || c.Intent == r.Intent
+
+	if r.Intent == Reject || c.Intent != intent.CreateIssue && c.Card == nil {
+		return r
+	}
+	idea, err := jira.GenerateIssueIdea(ctx, project, c.Message, c.Language)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Card = scoreCard(idea, c.Card)
+	return r
+}
+
+func scoreCard(idea *jira.IssueIdea, want *CardExpectation) *CardResult {
+	cfg := jira.CurrentConfig()
+	title, description := jira.ParseIssue(idea.RawOutput)
+	card := &CardResult{
+		Title:           title,
+		Description:     description,
+		Template:        idea.Template,
+		Language:        idea.Language,
+		Valid:           true,
+		WithinLimits:    true,
+		ExpectationsMet: true,
+	}
+	invalid := func(format string, args ...any) {
+		card.Valid = false
+		card.Problems = append(card.Problems, fmt.Sprintf(format, args...))
+	}
+	overLimit := func(format string, args ...any) {
+		card.WithinLimits = false
+		card.Problems = append(card.Problems, fmt.Sprintf(format, args...))
+	}
+	unmet := func(format string, args ...any) {
+		card.ExpectationsMet = false
+		card.Problems = append(card.Problems, fmt.Sprintf(format, args...))
+	}
+
+	if title == "" || title == "Untitled" {
+		invalid("missing title")
+	}
+	if description == "" || description == "No description provided" {
+		invalid("missing description")
+	}
+	policy := guard.OutputPolicy{AllowedHosts: cfg.Guard.AllowedHosts}
+	if u, err := url.Parse(cfg.Jira.BaseURL); err == nil && u.Hostname() != "" {
+		policy.AllowedHosts = append(slices.Clip(policy.AllowedHosts), u.Hostname())
+	}
+	for _, v := range policy.Check(title + "\n" + description) {
+		invalid("output policy: %s", v)
+	}
+
+	if n := utf8.RuneCountInString(title); n > cfg.Limits.TitleMaxLength {
+		overLimit("title is %d characters, limit %d", n, cfg.Limits.TitleMaxLength)
+	}
+	if n := utf8.RuneCountInString(description); n > cfg.Limits.DescriptionMaxLength {
+		overLimit("description is %d characters, limit %d", n, cfg.Limits.DescriptionMaxLength)
+	}
+
+	// Only the title is checked: a bilingual description mixes languages.
+	if got := lang.Detect(title); got != "" && got != idea.Language {
+		unmet("title is in %s, want %s", got, idea.Language)
+	}
+	if want != nil {
+		for _, s := range want.TitleContains {
+			if !containsFold(title, s) {
+				unmet("title does not contain %q", s)
+			}
+		}
+		for _, s := range want.DescriptionContains {
+			if !containsFold(description, s) {
+				unmet("description does not contain %q", s)
+			}
+		}
+		if n := utf8.RuneCountInString(description); n < want.DescriptionMinLength {
+			unmet("description is %d characters, want at least %d", n, want.DescriptionMinLength)
+		}
+	}
+	return card
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func summarize(results []Result) Summary {
+	s := Summary{Cases: len(results)}
+	for _, r := range results {
+		if r.Error != "" {
+			s.Errors++
+		}
+		if r.ExpectedIntent != "" {
+			s.IntentCases++
+			if r.IntentCorrect && r.Error == "" {
+				s.IntentCorrect++
+			}
+		}
+		if r.Intent == intent.Clarify {
+			s.Clarifications++
+		}
+		if r.Card == nil {
+			continue
+		}
+		s.Cards++
+		if r.Card.Valid {
+			s.ValidCards++
+		}
+		if r.Card.WithinLimits {
+			s.CardsInLimits++
+		}
+		if r.Card.ExpectationsMet {
+			s.ExpectationsMet++
+		}
+	}
+	return s
+}
+
+// Save writes r as indented JSON.
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadReport reads a report written by Save.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse report %s: %w", path, err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,175 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// metric is one summary line: a count out of a total.
+type metric struct {
+	name  string
+	count func(Summary) (int, int)
+}
+
+var metrics = []metric{
+	{"intent accuracy", func(s Summary) (int, int) { return s.IntentCorrect, s.IntentCases }},
+	{"card validity", func(s Summary) (int, int) { return s.ValidCards, s.Cards }},
+	{"length compliance", func(s Summary) (int, int) { return s.CardsInLimits, s.Cards }},
+	{"card expectations", func(s Summary) (int, int) { return s.ExpectationsMet, s.Cards }},
+	{"clarifications", func(s Summary) (int, int) { return s.Clarifications, s.Cases }},
+	{"errors", func(s Summary) (int, int) { return s.Errors, s.Cases }},
+}
+
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+func formatRate(n, total int) string {
+	if total == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%% (%d/%d)", rate(n, total), n, total)
+}
+
+func describe(r *Report) string {
+	var templates []string
+	for _, id := range r.Templates {
+		templates = append(templates, id)
+	}
+	sort.Strings(templates)
+	return fmt.Sprintf("model %s, embedding model %s, intents %s, templates %s", r.Model, r.EmbeddingModel, r.Intents, strings.Join(templates, " "))
+}
+
+// WriteSummary prints the scores of r and every case that failed a check.
+func WriteSummary(w io.Writer, r *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s (%s)\n\n", r.Label, describe(r))
+	for _, m := range metrics {
+		n, total := m.count(r.Summary)
+		fmt.Fprintf(tw, "%s\t%s\n", m.name, formatRate(n, total))
+	}
+
+	var failed []string
+	for _, res := range r.Results {
+		if problems := res.problems(); len(problems) > 0 {
+			failed = append(failed, fmt.Sprintf("  %s\t%s", res.Name, strings.Join(problems, "; ")))
+		}
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(tw, "\nFailed cases:\n%s\n", strings.Join(failed, "\n"))
+	}
+	return tw.Flush()
+}
+
+func (r Result) problems() []string {
+	var problems []string
+	if r.Error != "" {
+		problems = append(problems, "error: "+r.Error)
+	}
+	if !r.IntentCorrect && r.Error == "" {
+		problems = append(problems, fmt.Sprintf("classified %s (%.2f), want %s", r.Intent, r.Confidence, r.ExpectedIntent))
+	}
+	if r.Card != nil {
+		problems = append(problems, r.Card.Problems...)
+	}
+	return problems
+}
+
+// status condenses a result into the checks that changed between runs.
+func (r Result) status() string {
+	var parts []string
+	if r.Error != "" {
+		return "error"
+	}
+	if r.ExpectedIntent != "" {
+		mark := "ok"
+		if !r.IntentCorrect {
+			mark = "wrong"
+		}
+		parts = append(parts, fmt.Sprintf("intent %s (%s)", r.Intent, mark))
+	}
+	if r.Card != nil {
+		if len(r.Card.Problems) == 0 {
+			parts = append(parts, "card ok")
+		} else {
+			parts = append(parts, "card: "+strings.Join(r.Card.Problems, "; "))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r Result) passed() bool {
+	return len(r.problems()) == 0
+}
+
+// WriteDiff compares a baseline run with a candidate: the change in each
+// score, then every case whose outcome changed, regressions first.
+func WriteDiff(w io.Writer, base, cand *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "A: %s (%s)\n", base.Label, describe(base))
+	fmt.Fprintf(tw, "B: %s (%s)\n\n", cand.Label, describe(cand))
+
+	fmt.Fprintf(tw, "\tA\tB\tchange\n")
+	for _, m := range metrics {
+		an, at := m.count(base.Summary)
+		bn, bt := m.count(cand.Summary)
+		change := "n/a"
+		if at > 0 && bt > 0 {
+			change = fmt.Sprintf("%+.1f", rate(bn, bt)-rate(an, at))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.name, formatRate(an, at), formatRate(bn, bt), change)
+	}
+
+	byName := make(map[string]Result, len(base.Results))
+	for _, r := range base.Results {
+		byName[r.Name] = r
+	}
+	var regressed, fixed, changed, added []string
+	for _, b := range cand.Results {
+		a, ok := byName[b.Name]
+		if !ok {
+			added = append(added, fmt.Sprintf("  %s\t%s", b.Name, b.status()))
+			continue
+		}
+		delete(byName, b.Name)
+		if a.status() == b.status() {
+			continue
+		}
+		line := fmt.Sprintf("  %s\t%s\t→ %s", b.Name, a.status(), b.status())
+		switch {
+		case a.passed() && !b.passed():
+			regressed = append(regressed, line)
+		case !a.passed() && b.passed():
+			fixed = append(fixed, line)
+		default:
+			changed = append(changed, line)
+		}
+	}
+	var removed []string
+	for _, a := range base.Results {
+		if _, ok := byName[a.Name]; ok {
+			removed = append(removed, "  "+a.Name)
+		}
+	}
+
+	section := func(title string, lines []string) {
+		if len(lines) > 0 {
+			fmt.Fprintf(tw, "\n%s (%d):\n%s\n", title, len(lines), strings.Join(lines, "\n"))
+		}
+	}
+	section("Regressed", regressed)
+	section("Fixed", fixed)
+	section("Changed", changed)
+	section("Only in B", added)
+	section("Only in A", removed)
+	if len(regressed)+len(fixed)+len(changed)+len(added)+len(removed) == 0 {
+		fmt.Fprintln(tw, "\nNo case changed outcome.")
+	}
+	return tw.Flush()
+}
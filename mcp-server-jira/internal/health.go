@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+)
+
+// CheckJira verifies the Jira site is reachable and, when service account
+// credentials are configured, that they authenticate.
+func CheckJira(ctx context.Context) error {
+	cfg := settings().Jira
+	if cfg.BaseURL == "" {
+		return fmt.Errorf("jira.base_url is not configured")
+	}
+
+	operation, path := "server_info", "/rest/api/2/serverInfo"
+	creds, err := ServiceAccountCredentials()
+	if err == nil {
+		operation, path = "myself", "/rest/api/2/myself"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.BaseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if creds != nil {
+		req.Header.Set("Authorization", creds.Authorization())
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		metrics.ObserveJira(operation, 0)
+		return fmt.Errorf("Jira unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	metrics.ObserveJira(operation, resp.StatusCode)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("Jira rejected service account credentials: %s", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("Jira returned status: %s", resp.Status)
+	}
+	return nil
+}
+
+// CheckLLM verifies Ollama is reachable and has the configured models pulled.
+func CheckLLM(ctx context.Context) error {
+	cfg := settings().Ollama
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.BaseURL+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Ollama unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Ollama returned status: %s", resp.Status)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name  string `json:"name"`
+			Model string `json:"model"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return fmt.Errorf("failed to decode model list: %w", err)
+	}
+
+	loaded := map[string]bool{}
+	for _, m := range tags.Models {
+		loaded[m.Name] = true
+		loaded[m.Model] = true
+	}
+	models := []string{cfg.Model, cfg.EmbeddingModel}
+	if guard := settings().Guard; guard.Enabled && guard.LLMClassifier {
+		models = append(models, cfg.RoutingModel)
+	}
+	for _, model := range models {
+		if !loaded[model] && !loaded[model+":latest"] {
+			return fmt.Errorf("model %q is not available in Ollama", model)
+		}
+	}
+	return nil
+}
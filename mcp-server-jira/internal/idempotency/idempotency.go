@@ -0,0 +1,128 @@
+// Package idempotency remembers the result of a request by a client-chosen
+// key so that a retry returns the original result instead of repeating the
+// work.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrKeyReused is returned when a key is replayed with a different request.
+var ErrKeyReused = errors.New("idempotency key was already used for a different request")
+
+// errPanicked marks an attempt whose fn panicked instead of returning.
+var errPanicked = errors.New("idempotent request panicked")
+
+type entry[T any] struct {
+	fingerprint string
+	done        chan struct{}
+	result      T
+	err         error
+	expires     time.Time
+}
+
+// Store holds results in memory for a configurable window. Only successful
+// results are kept; a failed attempt is forgotten so it can be retried.
+type Store[T any] struct {
+	ttl atomic.Int64
+
+	mu        sync.Mutex
+	entries   map[string]*entry[T]
+	lastSweep time.Time
+}
+
+// New returns a Store that keeps results for ttl.
+func New[T any](ttl time.Duration) *Store[T] {
+	s := &Store[T]{entries: make(map[string]*entry[T])}
+	s.SetTTL(ttl)
+	return s
+}
+
+// SetTTL changes how long results completed from now on are kept.
+func (s *Store[T]) SetTTL(ttl time.Duration) {
+	s.ttl.Store(int64(ttl))
+}
+
+// Do runs fn once for key. A call with a key whose first attempt is still in
+// flight waits for it and shares its result; a call after it succeeded gets
+// the stored result with replayed set. fingerprint identifies the request so
+// that reusing a key for different input fails with ErrKeyReused.
+func (s *Store[T]) Do(ctx context.Context, key, fingerprint string, fn func() (T, error)) (result T, replayed bool, err error) {
+	for {
+		e, owner, err := s.acquire(key, fingerprint)
+		if err != nil {
+			return result, false, err
+		}
+		if owner {
+			result, err := s.run(key, e, fn)
+			return result, false, err
+		}
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return result, false, ctx.Err()
+		}
+		if e.err == nil {
+			return e.result, true, nil
+		}
+		// The attempt we waited on failed and has been forgotten; try to
+		// become the next owner.
+	}
+}
+
+// run calls fn as the owner of e. Completion is deferred so that waiters are
+// released, and the key freed for a retry, even if fn panics.
+func (s *Store[T]) run(key string, e *entry[T], fn func() (T, error)) (result T, err error) {
+	err = errPanicked
+	defer func() { s.complete(key, e, result, err) }()
+	return fn()
+}
+
+func (s *Store[T]) acquire(key, fingerprint string) (*entry[T], bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && (e.expires.IsZero() || now.Before(e.expires)) {
+		if e.fingerprint != fingerprint {
+			return nil, false, ErrKeyReused
+		}
+		return e, false, nil
+	}
+	e := &entry[T]{fingerprint: fingerprint, done: make(chan struct{})}
+	s.entries[key] = e
+	return e, true, nil
+}
+
+func (s *Store[T]) complete(key string, e *entry[T], result T, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.result, e.err = result, err
+	if err != nil {
+		delete(s.entries, key)
+	} else {
+		e.expires = time.Now().Add(time.Duration(s.ttl.Load()))
+	}
+	close(e.done)
+}
+
+// sweep drops expired results at most once a minute. Callers hold s.mu.
+func (s *Store[T]) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoReplaysResult(t *testing.T) {
+	s := New[string](time.Hour)
+	var calls int
+	fn := func() (string, error) {
+		calls++
+		return "AIT-1", nil
+	}
+
+	got, replayed, err := s.Do(context.Background(), "k", "fp", fn)
+	if err != nil || got != "AIT-1" || replayed {
+		t.Fatalf("first Do = %q, %v, %v", got, replayed, err)
+	}
+	got, replayed, err = s.Do(context.Background(), "k", "fp", fn)
+	if err != nil || got != "AIT-1" || !replayed {
+		t.Fatalf("second Do = %q, %v, %v", got, replayed, err)
+	}
+	if calls != 1 {
+		t.Errorf("fn ran %d times, want 1", calls)
+	}
+
+	if _, _, err := s.Do(context.Background(), "k", "other", fn); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("different fingerprint: got %v, want ErrKeyReused", err)
+	}
+}
+
+func TestDoConcurrentReplay(t *testing.T) {
+	s := New[string](time.Hour)
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+
+	type outcome struct {
+		result   string
+		replayed bool
+		err      error
+	}
+	first := make(chan outcome, 1)
+	go func() {
+		r, replayed, err := s.Do(context.Background(), "k", "fp", func() (string, error) {
+			calls.Add(1)
+			close(started)
+			<-release
+			return "AIT-1", nil
+		})
+		first <- outcome{r, replayed, err}
+	}()
+	<-started
+
+	// The second call arrives while the first is still running.
+	second := make(chan outcome, 1)
+	go func() {
+		r, replayed, err := s.Do(context.Background(), "k", "fp", func() (string, error) {
+			calls.Add(1)
+			return "AIT-2", nil
+		})
+		second <- outcome{r, replayed, err}
+	}()
+	select {
+	case o := <-second:
+		t.Fatalf("second call returned %+v before the first finished", o)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	if o := <-first; o.err != nil || o.result != "AIT-1" || o.replayed {
+		t.Errorf("first = %+v", o)
+	}
+	if o := <-second; o.err != nil || o.result != "AIT-1" || !o.replayed {
+		t.Errorf("second = %+v, want the first call's result replayed", o)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn ran %d times, want 1", n)
+	}
+}
+
+func TestDoForgetsFailures(t *testing.T) {
+	s := New[string](time.Hour)
+	boom := errors.New("jira unavailable")
+	if _, _, err := s.Do(context.Background(), "k", "fp", func() (string, error) { return "", boom }); !errors.Is(err, boom) {
+		t.Fatalf("got %v, want %v", err, boom)
+	}
+	got, replayed, err := s.Do(context.Background(), "k", "fp", func() (string, error) { return "AIT-1", nil })
+	if err != nil || got != "AIT-1" || replayed {
+		t.Errorf("retry after failure = %q, %v, %v", got, replayed, err)
+	}
+}
+
+func TestDoPanicReleasesWaiters(t *testing.T) {
+	s := New[string](time.Hour)
+	started, release := make(chan struct{}), make(chan struct{})
+
+	panicked := make(chan any, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		s.Do(context.Background(), "k", "fp", func() (string, error) {
+			close(started)
+			<-release
+			panic("handler bug")
+		})
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		got, replayed, err := s.Do(context.Background(), "k", "fp", func() (string, error) { return "AIT-1", nil })
+		if err == nil && (got != "AIT-1" || replayed) {
+			err = errors.New("waiter did not run its own attempt")
+		}
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if p := <-panicked; p != "handler bug" {
+		t.Errorf("recovered %v, want the original panic", p)
+	}
+	select {
+	case err := <-waiter:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter still blocked after the owner panicked")
+	}
+}
+
+func TestDoWaiterContextCancelled(t *testing.T) {
+	s := New[string](time.Hour)
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	go s.Do(context.Background(), "k", "fp", func() (string, error) {
+		close(started)
+		<-release
+		return "AIT-1", nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := s.Do(ctx, "k", "fp", func() (string, error) { return "AIT-2", nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestDoExpires(t *testing.T) {
+	s := New[string](time.Millisecond)
+	s.Do(context.Background(), "k", "fp", func() (string, error) { return "AIT-1", nil })
+	time.Sleep(5 * time.Millisecond)
+
+	got, replayed, err := s.Do(context.Background(), "k", "other", func() (string, error) { return "AIT-2", nil })
+	if err != nil || got != "AIT-2" || replayed {
+		t.Errorf("Do after expiry = %q, %v, %v", got, replayed, err)
+	}
+}
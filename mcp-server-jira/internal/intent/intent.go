@@ -0,0 +1,272 @@
+// Package intent classifies chat messages by comparing their embeddings with
+// the embeddings of example utterances for each intent. Intents and examples
+// are data: the built-in set is embedded in the binary and an intents file
+// may replace it.
+package intent
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in intent names. The server acts on these; an intents file may add
+// others, which are recognised but answered as not yet supported.
+const (
+	CreateIssue = "create_issue"
+	Search      = "search"
+	Comment     = "comment"
+	Transition  = "transition"
+	Summarize   = "summarize"
+	ChitChat    = "chit_chat"
+)
+
+// Clarify is the result of a classification that was not confident enough
+// to act on.
+const Clarify = "clarify"
+
+// Intent is one thing a message can ask for. Description completes the
+// sentence "I can ..." in clarifying questions.
+type Intent struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Examples    []string `yaml:"examples"`
+}
+
+// Set is a loaded list of intents.
+type Set struct {
+	Intents []Intent `yaml:"intents"`
+	// Source is the file the set came from, or "builtin".
+	Source string `yaml:"-"`
+}
+
+// Lookup returns the named intent.
+func (s *Set) Lookup(name string) (Intent, bool) {
+	for _, in := range s.Intents {
+		if in.Name == name {
+			return in, true
+		}
+	}
+	return Intent{}, false
+}
+
+//go:embed defaults.yaml
+var defaultsYAML []byte
+
+var builtin = mustLoadBuiltin()
+
+// Builtin returns the intents embedded in the binary.
+func Builtin() *Set {
+	return builtin
+}
+
+func mustLoadBuiltin() *Set {
+	s, err := parse("builtin", defaultsYAML)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Load reads an intents file. An empty path returns the built-in set.
+func Load(path string) (*Set, error) {
+	if path == "" {
+		return builtin, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read intents file: %w", err)
+	}
+	return parse(path, data)
+}
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func parse(source string, data []byte) (*Set, error) {
+	var s Set
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("parse intents %s: %w", source, err)
+	}
+	s.Source = source
+
+	var errs []error
+	seen := make(map[string]bool)
+	for i, in := range s.Intents {
+		switch {
+		case !namePattern.MatchString(in.Name):
+			errs = append(errs, fmt.Errorf("intent %d: name %q must be lower case letters, digits and underscores", i+1, in.Name))
+		case in.Name == Clarify:
+			errs = append(errs, fmt.Errorf("intent %d: %q is reserved", i+1, Clarify))
+		case seen[in.Name]:
+			errs = append(errs, fmt.Errorf("intent %q: duplicate name", in.Name))
+		}
+		seen[in.Name] = true
+		if strings.TrimSpace(in.Description) == "" {
+			s.Intents[i].Description = strings.ReplaceAll(in.Name, "_", " ")
+		}
+		examples := in.Examples[:0]
+		for _, e := range in.Examples {
+			if e = strings.TrimSpace(e); e != "" {
+				examples = append(examples, e)
+			}
+		}
+		s.Intents[i].Examples = examples
+		if len(examples) == 0 {
+			errs = append(errs, fmt.Errorf("intent %q: at least one example is required", in.Name))
+		}
+	}
+	if len(s.Intents) == 0 {
+		errs = append(errs, errors.New("no intents defined"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid intents %s:\n%w", source, err)
+	}
+	return &s, nil
+}
+
+// Embedder returns one embedding per text, in order, all computed by the
+// named model.
+type Embedder func(ctx context.Context, model string, texts []string) ([][]float64, error)
+
+// Thresholds decide when a classification is too uncertain to act on.
+type Thresholds struct {
+	// MinConfidence is the lowest similarity acted on.
+	MinConfidence float64
+	// MinMargin is the lead the best intent needs over the runner-up.
+	MinMargin float64
+}
+
+// Score is the similarity of a message to an intent's closest example.
+type Score struct {
+	Intent string  `json:"intent"`
+	Score  float64 `json:"score"`
+}
+
+// Result is a classification. Intent is Clarify when the best score is
+// below the minimum confidence or too close to the runner-up; Guess then
+// names the best intent anyway. Scores are sorted best first.
+type Result struct {
+	Intent     string
+	Guess      string
+	Confidence float64
+	Scores     []Score
+}
+
+// Classifier matches messages against a Set. The embeddings of the examples
+// are computed on first use and cached per embedding model.
+type Classifier struct {
+	set *Set
+
+	mu       sync.Mutex
+	examples map[string][][]float64
+}
+
+// NewClassifier returns a classifier for set.
+func NewClassifier(set *Set) *Classifier {
+	return &Classifier{set: set, examples: make(map[string][][]float64)}
+}
+
+// Set returns the intents c classifies into.
+func (c *Classifier) Set() *Set {
+	return c.set
+}
+
+// Classify embeds text with model and scores it against every intent. Each
+// intent scores the cosine similarity of its closest example.
+func (c *Classifier) Classify(ctx context.Context, embed Embedder, model, text string, t Thresholds) (Result, error) {
+	examples, err := c.exampleEmbeddings(ctx, embed, model)
+	if err != nil {
+		return Result{}, err
+	}
+	vectors, err := embed(ctx, model, []string{text})
+	if err != nil {
+		return Result{}, err
+	}
+	if len(vectors) != 1 {
+		return Result{}, fmt.Errorf("embedding model returned %d embeddings for 1 message", len(vectors))
+	}
+	message := vectors[0]
+
+	var r Result
+	n := 0
+	for _, in := range c.set.Intents {
+		best := -1.0
+		for range in.Examples {
+			sim, err := cosine(message, examples[n])
+			if err != nil {
+				return Result{}, err
+			}
+			best = max(best, sim)
+			n++
+		}
+		r.Scores = append(r.Scores, Score{Intent: in.Name, Score: best})
+	}
+	sort.SliceStable(r.Scores, func(i, j int) bool { return r.Scores[i].Score > r.Scores[j].Score })
+
+	top := r.Scores[0]
+	r.Intent, r.Guess, r.Confidence = top.Intent, top.Intent, top.Score
+	margin := math.Inf(1)
+	if len(r.Scores) > 1 {
+		margin = top.Score - r.Scores[1].Score
+	}
+	if top.Score < t.MinConfidence || margin < t.MinMargin {
+		r.Intent = Clarify
+	}
+	return r, nil
+}
+
+// exampleEmbeddings returns the embedding of every example, intent by
+// intent, computing them on the first call for model.
+func (c *Classifier) exampleEmbeddings(ctx context.Context, embed Embedder, model string) ([][]float64, error) {
+	c.mu.Lock()
+	cached, ok := c.examples[model]
+	c.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var texts []string
+	for _, in := range c.set.Intents {
+		texts = append(texts, in.Examples...)
+	}
+	vectors, err := embed(ctx, model, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed intent examples: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedding model returned %d embeddings for %d examples", len(vectors), len(texts))
+	}
+
+	c.mu.Lock()
+	c.examples[model] = vectors
+	c.mu.Unlock()
+	return vectors, nil
+}
+
+func cosine(a, b []float64) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("embedding dimensions differ: %d and %d", len(a), len(b))
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0, nil
+	}
+	return dot / math.Sqrt(na*nb), nil
+}
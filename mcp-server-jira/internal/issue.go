@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
+)
+
+// issueDetailFields are the fields GetIssue reads.
+var issueDetailFields = []string{
+	"summary", "status", "issuetype", "assignee", "reporter", "priority", "labels",
+	"created", "updated", "description", "comment", "issuelinks", "subtasks", "parent",
+}
+
+// IssueDetail is an issue with its comments, links and subtasks.
+type IssueDetail struct {
+	Issue
+	Comments []Comment
+	Links    []IssueLink
+	// Subtasks and linked issues carry key, summary, status, type and
+	// priority only.
+	Subtasks []Issue
+	// ParentKey is the issue this is a subtask of, or empty.
+	ParentKey string
+}
+
+// Comment is a comment on an issue.
+type Comment struct {
+	ID      string
+	Author  string
+	Body    string
+	Created time.Time
+}
+
+// IssueLink is a link to Issue, described from the linking issue's end,
+// such as "blocks" or "is blocked by".
+type IssueLink struct {
+	Relation string
+	Issue    Issue
+}
+
+// GetIssue reads the issue with key as the caller identified by creds.
+func GetIssue(ctx context.Context, creds *Credentials, key string) (*IssueDetail, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "GetIssue")
+	defer span.End()
+	span.SetAttributes(attribute.String("jira.issue_key", key))
+
+	if creds == nil {
+		return nil, ErrNoCredentials
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=" + strings.Join(issueDetailFields, ",")
+	var is issueFields
+	if err := jiraRead(ctx, creds, "get_issue", http.MethodGet, path, nil, &is); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	f := is.Fields
+	detail := &IssueDetail{Issue: is.issue()}
+	for _, c := range f.Comment.Comments {
+		detail.Comments = append(detail.Comments, Comment{ID: c.ID, Author: c.Author.String(), Body: c.Body, Created: c.Created.Time})
+	}
+	for _, l := range f.IssueLinks {
+		switch {
+		case l.OutwardIssue != nil:
+			detail.Links = append(detail.Links, IssueLink{Relation: l.Type.Outward, Issue: l.OutwardIssue.issue()})
+		case l.InwardIssue != nil:
+			detail.Links = append(detail.Links, IssueLink{Relation: l.Type.Inward, Issue: l.InwardIssue.issue()})
+		}
+	}
+	for _, sub := range f.Subtasks {
+		detail.Subtasks = append(detail.Subtasks, sub.issue())
+	}
+	if f.Parent != nil {
+		detail.ParentKey = f.Parent.Key
+	}
+	slog.InfoContext(ctx, "Read Jira issue", "issue_key", is.Key, "comments", len(detail.Comments), "links", len(detail.Links), "subtasks", len(detail.Subtasks))
+	return detail, nil
+}
+
+type jiraComment struct {
+	ID      string    `json:"id"`
+	Author  *jiraUser `json:"author"`
+	Body    string    `json:"body"`
+	Created jiraTime  `json:"created"`
+}
+
+// jiraLink is an issue link. Exactly one of InwardIssue and OutwardIssue is
+// set: the other end of the link, seen from the issue it was read from.
+type jiraLink struct {
+	Type struct {
+		Inward  string `json:"inward"`
+		Outward string `json:"outward"`
+	} `json:"type"`
+	InwardIssue  *issueFields `json:"inwardIssue"`
+	OutwardIssue *issueFields `json:"outwardIssue"`
+}
@@ -2,31 +2,68 @@ package internal
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/audit"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
 )
 
-func CreateIssue(projectKey, title, description string) (string, error) {
-	fmt.Println("-------------- MCP Server Jira Create Issue ------------------")
-	jiraBaseURL := os.Getenv("JIRA_BASE_URL")
-	jiraEmail := os.Getenv("JIRA_EMAIL")
-	jiraToken := os.Getenv("JIRA_API_TOKEN")
+// jiraWriteTimeout bounds a Jira write once it has been committed to.
+const jiraWriteTimeout = 30 * time.Second
+
+// CreateIssue files idea in projectKey as the caller identified by creds and
+// records the attempt, successful or not, in the audit log.
+func CreateIssue(ctx context.Context, creds *Credentials, projectKey string, idea *IssueIdea) (issueKey string, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CreateIssue", trace.WithAttributes(
+		attribute.String("jira.project", projectKey),
+	))
+	defer span.End()
 
-	if jiraBaseURL == "" || jiraEmail == "" || jiraToken == "" {
-		return "", fmt.Errorf("missing required Jira credentials or URL in environment variables")
+	if creds == nil {
+		return "", ErrNoCredentials
+	}
+	// A caller that has gone away must never get a card it no longer wants.
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("request ended before creating Jira issue: %w", err)
+	}
+	if err := checkOutput(ctx, creds, projectKey, idea); err != nil {
+		return "", err
 	}
 
-	auth := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", jiraEmail, jiraToken)))
+	entry := audit.Entry{
+		Action:         audit.ActionCreate,
+		Caller:         creds.Caller(),
+		Project:        projectKey,
+		Prompt:         idea.Prompt,
+		Model:          idea.Model,
+		Template:       idea.Template,
+		Language:       idea.Language,
+		LLMOutput:      idea.RawOutput,
+		DescriptionFit: idea.DescriptionFit,
+	}
+	defer func() {
+		entry.IssueKey = issueKey
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		recordAudit(ctx, entry)
+	}()
 
 	payload := map[string]interface{}{
 		"fields": map[string]interface{}{
 			"project":     map[string]string{"key": projectKey},
-			"summary":     title,
-			"description": description,
+			"summary":     idea.Title,
+			"description": idea.Description,
 			"issuetype":   map[string]string{"name": "Task"},
 		},
 	}
@@ -34,36 +71,51 @@ func CreateIssue(projectKey, title, description string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal issue payload: %w", err)
 	}
-	fmt.Println("Jira CreateIssue JSON payload:", string(body))
+	entry.Payload = body
+	slog.DebugContext(ctx, "Jira CreateIssue payload", "payload", string(body))
+
+	// Once the request is sent Jira may create the issue, so see it through
+	// even if the caller cancels; otherwise the card would exist without its
+	// key being returned or audited.
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jiraWriteTimeout)
+	defer cancel()
 
-	req, err := http.NewRequest("POST", jiraBaseURL+"/rest/api/2/issue", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(writeCtx, "POST", creds.BaseURL+"/rest/api/2/issue", bytes.NewBuffer(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Basic "+auth)
+	req.Header.Set("Authorization", creds.Authorization())
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := jiraHTTP.Do(req)
 	if err != nil {
+		span.RecordError(err)
+		metrics.ObserveJira("create_issue", 0)
 		return "", fmt.Errorf("failed to perform request: %w", err)
 	}
 	defer resp.Body.Close()
+	metrics.ObserveJira("create_issue", resp.StatusCode)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	entry.JiraStatus, entry.JiraResponse = resp.StatusCode, string(respBody)
 
 	if resp.StatusCode != http.StatusCreated {
-		respBody, _ := io.ReadAll(resp.Body)
-		fmt.Printf("Jira API returned status: %s\nResponse body: %s\n", resp.Status, string(respBody))
-		return "", fmt.Errorf("Jira API returned status: %s", resp.Status)
+		slog.WarnContext(ctx, "Jira API rejected CreateIssue", "status", resp.Status, "body", string(respBody))
+		return "", newAPIError("jira", "create_issue", resp, respBody)
 	}
 
 	var result struct {
 		Key string `json:"key"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(respBody, &result); err != nil {
 		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	fmt.Println("-------------- MCP Server Jira Create Issue End ------------------")
+	span.SetAttributes(attribute.String("jira.issue_key", result.Key))
+	slog.InfoContext(ctx, "Jira issue created", "issue_key", result.Key, "project", projectKey)
 	return result.Key, nil
 }
@@ -0,0 +1,94 @@
+// Package lang names the languages issues can be written in and detects
+// the language of a message from the scripts it is written in.
+package lang
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Auto as a configured language means "the language of the message".
+const Auto = "auto"
+
+// English is the fallback when a message's language cannot be told.
+const English = "en"
+
+// names maps the supported language codes to the names used in prompts.
+var names = map[string]string{
+	"en": "English",
+	"th": "Thai",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"zh": "Chinese",
+}
+
+// Name returns the English name of a supported language code.
+func Name(code string) (string, bool) {
+	name, ok := names[code]
+	return name, ok
+}
+
+// Codes lists the supported language codes, sorted.
+func Codes() []string {
+	codes := make([]string, 0, len(names))
+	for code := range names {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// minScriptShare is the share of a message's letters a non-Latin script
+// needs to decide its language. Thai and CJK requests routinely embed
+// English product names and identifiers, so those scripts win without a
+// majority.
+const minScriptShare = 0.25
+
+// Detect returns the language code of text, judged by script: Thai script
+// is Thai, kana is Japanese, Hangul is Korean, other Han characters are
+// Chinese and Latin script is English. It returns "" for text without
+// letters.
+func Detect(text string) string {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts["ja"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["en"]++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Japanese mixes kana with Han characters.
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+
+	best, bestCount := "", 0
+	for _, code := range Codes() {
+		if code != English && counts[code] > bestCount {
+			best, bestCount = code, counts[code]
+		}
+	}
+	if best != "" && float64(bestCount) >= minScriptShare*float64(letters) {
+		return best
+	}
+	if counts[English] > 0 {
+		return English
+	}
+	return best
+}
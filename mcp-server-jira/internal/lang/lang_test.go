@@ -0,0 +1,25 @@
+package lang
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		text, want string
+	}{
+		{"Create a card for the login bug", "en"},
+		{"สร้างการ์ดสำหรับบั๊กหน้าเข้าสู่ระบบ", "th"},
+		{"สวัสดีครับ", "th"},
+		{"แก้ OAuth callback ที่ใช้ไม่ได้บน Safari", "th"},
+		{"Fix the OAuth callback for the admin console, ด่วน", "en"},
+		{"ログインのバグを修正する", "ja"},
+		{"로그인 버그 수정", "ko"},
+		{"修复登录错误", "zh"},
+		{"AIT-42 !!! 123", "en"},
+		{"1234 ... ?", ""},
+		{"", ""},
+	} {
+		if got := Detect(tc.text); got != tc.want {
+			t.Errorf("Detect(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
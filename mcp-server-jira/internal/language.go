@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/lang"
+)
+
+// issueLanguage returns the language an issue generated from message is
+// written in and the language message itself is in. requested, when not
+// empty, overrides language.projects and language.default.
+func issueLanguage(projectKey, requested, message string) (target, source string) {
+	source = lang.Detect(message)
+	if source == "" {
+		source = lang.English
+	}
+
+	cfg := settings().Language
+	target = requested
+	if target == "" {
+		target = cfg.Projects[projectKey]
+	}
+	if target == "" {
+		target = cfg.Default
+	}
+	if target == lang.Auto || target == "" {
+		target = source
+	}
+	return target, source
+}
@@ -7,134 +7,235 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"os"
 	"regexp"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/intent"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/textfit"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
 )
 
+// IssueIdea is a generated issue together with the prompt, model, template
+// and raw model output it came from, which are kept for the audit log.
 type IssueIdea struct {
 	Title       string
 	Description string
+	Prompt      string
+	Model       string
+	// Template is the ID of the prompt template used, e.g. "generate@1",
+	// followed by ",shorten@1" when the description was summarised.
+	Template string
+	// Language is the code of the language the issue was asked for in.
+	Language string
+	// DescriptionFit is how a description over the limit was brought
+	// within it: FitSummarized or FitTruncated. It is empty when the
+	// description fit as generated.
+	DescriptionFit string
+	RawOutput      string
 }
 
+// Ways an over-long description is made to fit.
+const (
+	FitSummarized = "summarized"
+	FitTruncated  = "truncated"
+)
+
 type OllamaResponse struct {
-	Response string `json:"response"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
-func ReceivePrompt(prompt string) (string, error) {
-	decisionPrompt := fmt.Sprintf(`You are a routing assistant. I will give you a message, and you need to decide how to handle it.
-
-Answer "mcp" if the message requires any of these actions:
-- Creating Jira cards/issues/tickets
-- Updating Jira issues
-- Syncing with Jira
-- Any task management or project management actions
-- Technical implementation tasks that need to be tracked
-
-Answer "local" if the message is:
-- Simple greetings (Hello, Hi, สวัสดี, etc.)
-- General questions
-- Casual conversation
-- Requests for information only
-
-User message: %s
+// Reply is the answer to a chat message and the intent it was routed to.
+// Intent is intent.Clarify when the router asked the user to rephrase.
+type Reply struct {
+	Message    string
+	Intent     string
+	Confidence float64
+}
 
-Your answer (mcp or local):`, prompt)
+// ReceivePrompt screens a chat message, classifies its intent and acts on
+// it. Issue creation, search and chit-chat are handled so far; other
+// recognised intents are acknowledged without touching Jira.
+func ReceivePrompt(ctx context.Context, prompt string, creds *Credentials) (*Reply, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ReceivePrompt")
+	defer span.End()
 
-	fmt.Printf("🔍 DEBUG: Sending decision prompt to Ollama: %s\n", decisionPrompt)
+	projectKey := settings().Jira.ProjectKey
+	if err := ScreenPrompt(ctx, creds, projectKey, prompt); err != nil {
+		return nil, err
+	}
 
-	response, err := callOllama(decisionPrompt)
+	result, err := ClassifyMessage(ctx, prompt)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	fmt.Printf("🔍 DEBUG: Ollama response: '%s'\n", response)
-
-	cleaned := strings.ToLower(strings.TrimSpace(response))
-	fmt.Printf("🔍 DEBUG: Cleaned response: '%s'\n", cleaned)
-
-	if strings.Contains(cleaned, "mcp") {
-		fmt.Printf("🔍 DEBUG: Routing to MCP server\n")
-		return talkToMCPServer(prompt)
-	} else if strings.Contains(cleaned, "local") {
-		fmt.Printf("🔍 DEBUG: Routing to local handler\n")
-		return talkLocally(prompt)
-	} else {
-		fmt.Printf("🔍 DEBUG: No clear routing decision, defaulting to local\n")
-		return talkLocally(prompt)
+	reply := &Reply{Intent: result.Intent, Confidence: result.Confidence}
+
+	set := CurrentIntents()
+	switch result.Intent {
+	case intent.Clarify:
+		reply.Message = clarification(set, result)
+	case intent.CreateIssue:
+		reply.Message, err = createFromMessage(ctx, projectKey, prompt, creds)
+	case intent.Search:
+		reply.Message, err = searchFromMessage(ctx, projectKey, prompt, creds)
+	case intent.ChitChat:
+		reply.Message, err = talkLocally(prompt)
+	default:
+		in, _ := set.Lookup(result.Intent)
+		reply.Message = fmt.Sprintf("I understood that as a request to %s, which I can't do from chat yet.", in.Description)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return reply, nil
 }
 
-func callOllama(prompt string) (string, error) {
+// callOllama sends prompt to model and returns the whole answer. operation
+// labels the call in metrics and errors.
+func callOllama(ctx context.Context, model, operation, prompt string) (string, error) {
+	cfg := settings().Ollama
+	ctx, span := tracing.Tracer().Start(ctx, "callOllama", trace.WithAttributes(
+		attribute.String("llm.model", model),
+		attribute.String("llm.operation", operation),
+	))
+	defer span.End()
+
 	payload := map[string]interface{}{
-		"model":  "llama3",
+		"model":  model,
 		"prompt": prompt,
 		"stream": false,
 	}
 
 	jsonPayload, _ := json.Marshal(payload)
 
-	ollamaURL := os.Getenv("OLLAMA_BASE_URL")
-	if ollamaURL == "" {
-		ollamaURL = "http://localhost:11434"
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.BaseURL+"/api/generate", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return "", err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.Post(ollamaURL+"/api/generate", "application/json", bytes.NewBuffer(jsonPayload))
+	start := time.Now()
+	resp, err := ollamaHTTP.DoIdempotent(req)
 	if err != nil {
+		span.RecordError(err)
+		metrics.ObserveLLM(model, operation, time.Since(start), 0, 0)
 		return "", err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		metrics.ObserveLLM(model, operation, time.Since(start), 0, 0)
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", newAPIError("ollama", operation, resp, bodyBytes)
+	}
+
 	var result OllamaResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		metrics.ObserveLLM(model, operation, time.Since(start), 0, 0)
 		return "", err
 	}
+	metrics.ObserveLLM(model, operation, time.Since(start), result.PromptEvalCount, result.EvalCount)
 
 	return result.Response, nil
 }
 
-func talkToMCPServer(prompt string) (string, error) {
-	lowerPrompt := strings.ToLower(prompt)
-	if strings.Contains(lowerPrompt, "create") && (strings.Contains(lowerPrompt, "card") || strings.Contains(lowerPrompt, "issue") || strings.Contains(lowerPrompt, "ticket") || strings.Contains(lowerPrompt, "jira")) {
-		fmt.Println("Detected Jira card creation request via message command")
+// createFromMessage generates an issue from a chat message and creates it
+// in projectKey.
+func createFromMessage(ctx context.Context, projectKey, prompt string, creds *Credentials) (string, error) {
+	issueIdea, err := GenerateIssueIdea(ctx, projectKey, prompt, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate issue idea: %w", err)
+	}
 
-		projectKey := os.Getenv("JIRA_PROJECT_KEY")
-		if projectKey == "" {
-			projectKey = "PROJ"
-		}
+	issueKey, err := CreateIssue(ctx, creds, projectKey, issueIdea)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Jira issue: %w", err)
+	}
 
-		issueIdea, err := GenerateIssueIdea(prompt)
-		if err != nil {
-			return "", fmt.Errorf("failed to generate issue idea: %w", err)
-		}
+	return fmt.Sprintf("✅ Created Jira card: %s\nTitle: %s\nDescription: %s", issueKey, issueIdea.Title, issueIdea.Description), nil
+}
 
-		issueKey, err := CreateIssue(projectKey, issueIdea.Title, issueIdea.Description)
-		if err != nil {
-			return "", fmt.Errorf("failed to create Jira issue: %w", err)
-		}
+// chatSearchResults is how many issues a search from chat lists.
+const chatSearchResults = 10
 
-		return fmt.Sprintf("✅ Created Jira card: %s\nTitle: %s\nDescription: %s", issueKey, issueIdea.Title, issueIdea.Description), nil
+// searchFromMessage answers a chat question with a Jira search in
+// projectKey. The message was screened by ReceivePrompt already, so it is
+// translated and checked here rather than through SearchIssues' question
+// path, which would screen it again.
+func searchFromMessage(ctx context.Context, projectKey, prompt string, creds *Credentials) (string, error) {
+	if creds == nil {
+		return "", ErrNoCredentials
+	}
+	jql, err := TranslateJQL(ctx, projectKey, prompt)
+	if err != nil {
+		return "", err
+	}
+	if err := checkJQL(ctx, creds, jql); err != nil {
+		return "", err
+	}
+	result, err := SearchIssues(ctx, creds, SearchQuery{
+		JQL:        jql,
+		MaxResults: chatSearchResults,
+		Fields:     []string{"summary", "status", "assignee"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search issues: %w", err)
 	}
 
-	return "Sending to MCP server: " + prompt, nil
+	if len(result.Issues) == 0 {
+		return fmt.Sprintf("🔎 No issues found.\nJQL: %s", jql), nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔎 Found %d issues.\nJQL: %s\n", result.Total, jql)
+	for _, is := range result.Issues {
+		fmt.Fprintf(&b, "\n%s [%s] %s", is.Key, is.Status, is.Summary)
+		if is.Assignee != "" {
+			fmt.Fprintf(&b, " (%s)", is.Assignee)
+		}
+	}
+	if more := result.Total - len(result.Issues); more > 0 {
+		fmt.Fprintf(&b, "\n…and %d more.", more)
+	}
+	return b.String(), nil
 }
 
 func talkLocally(prompt string) (string, error) {
 	return "Answer locally: " + prompt, nil
 }
 
-func GenerateIssueIdea(prompt string) (*IssueIdea, error) {
-	fmt.Println("-------------- MCP Server Jira Generate Issue Idea ------------------")
-
+// GenerateIssueIdea asks the model for an issue for prompt, using the
+// generate template for projectKey. language, when not empty, is the code of
+// the language to write the issue in instead of the configured one.
+func GenerateIssueIdea(ctx context.Context, projectKey, prompt, language string) (*IssueIdea, error) {
+	cfg := settings()
+	target, source := issueLanguage(projectKey, language, prompt)
+	ctx, span := tracing.Tracer().Start(ctx, "GenerateIssueIdea", trace.WithAttributes(
+		attribute.String("llm.model", cfg.Ollama.Model),
+		attribute.String("llm.language", target),
+		attribute.String("prompt.language", source),
+	))
+	defer span.End()
+
+	generatePrompt, templateID, err := RenderPrompt(prompts.Generate, projectKey, prompt, language)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("llm.prompt_template", templateID))
+	slog.DebugContext(ctx, "Generating issue", "language", target, "prompt_language", source)
 	payload := map[string]interface{}{
-		"model": "llama3",
-		"prompt": prompt + "\n\nJIRA requirements:\n" +
-			"1. Title must be less than 255 characters.\n" +
-			"2. Description must be less than 1000 characters.\n" +
-			"3. Summary must be less than 255 characters.\n\n" +
-			"Please respond in this format:\nTitle: <your title here>\nDescription:\n<your description here>",
+		"model":  cfg.Ollama.Model,
+		"prompt": generatePrompt,
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -142,31 +243,34 @@ func GenerateIssueIdea(prompt string) (*IssueIdea, error) {
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	// The timeout covers generation only; shortening an over-long
+	// description gets its own.
+	generateCtx, cancel := context.WithTimeout(ctx, cfg.Ollama.Timeout)
 	defer cancel()
 
-	ollamaURL := os.Getenv("OLLAMA_BASE_URL")
-	if ollamaURL == "" {
-		ollamaURL = "http://localhost:11434"
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", ollamaURL+"/api/generate", bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(generateCtx, "POST", cfg.Ollama.BaseURL+"/api/generate", bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	start := time.Now()
+	var promptTokens, completionTokens int
+	defer func() {
+		metrics.ObserveLLM(cfg.Ollama.Model, "generate", time.Since(start), promptTokens, completionTokens)
+	}()
+
+	resp, err := ollamaHTTP.DoIdempotent(req)
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("request to Ollama failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Ollama API returned status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, newAPIError("ollama", "generate", resp, bodyBytes)
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
@@ -174,15 +278,17 @@ func GenerateIssueIdea(prompt string) (*IssueIdea, error) {
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
-		var chunk struct {
-			Response string `json:"response"`
-			Done     bool   `json:"done"`
-		}
+		var chunk OllamaResponse
 		if err := json.Unmarshal(line, &chunk); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal chunk: %w", err)
 		}
 		fullResponse.WriteString(chunk.Response)
 		if chunk.Done {
+			promptTokens, completionTokens = chunk.PromptEvalCount, chunk.EvalCount
+			span.SetAttributes(
+				attribute.Int("llm.prompt_tokens", promptTokens),
+				attribute.Int("llm.completion_tokens", completionTokens),
+			)
 			break
 		}
 	}
@@ -193,15 +299,76 @@ func GenerateIssueIdea(prompt string) (*IssueIdea, error) {
 
 	content := fullResponse.String()
 
-	title := extractTitle(content)
-	description := extractDescription(content)
+	title, description := ParseIssue(content)
+	idea := &IssueIdea{
+		Title:       sanitizeTitle(title),
+		Description: description,
+		Prompt:      prompt,
+		Model:       cfg.Ollama.Model,
+		Template:    templateID,
+		Language:    target,
+		RawOutput:   content,
+	}
+	fitDescription(ctx, projectKey, idea)
+	return idea, nil
+}
 
-	title = sanitizeTitle(title)
+// fitDescription brings idea's description within the configured limit.
+// With limits.summarize_overflow on, the model is asked to shorten it
+// first; whatever is still too long is truncated.
+func fitDescription(ctx context.Context, projectKey string, idea *IssueIdea) {
+	cfg := settings()
+	limit := cfg.Limits.DescriptionMaxLength
+	length := textfit.Len(idea.Description)
+	if length <= limit {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("llm.description_length", length))
+
+	if cfg.Limits.SummarizeOverflow {
+		summary, templateID, err := shortenDescription(ctx, projectKey, idea.Description, idea.Language)
+		switch {
+		case err != nil:
+			slog.WarnContext(ctx, "Failed to shorten description, truncating it", "length", length, "limit", limit, "error", err)
+		case summary == "":
+			slog.WarnContext(ctx, "Model returned an empty shortened description, truncating the original", "length", length, "limit", limit)
+		default:
+			idea.Template += "," + templateID
+			idea.Description = summary
+			n := textfit.Len(summary)
+			if n <= limit {
+				slog.InfoContext(ctx, "Shortened over-long description", "length", length, "shortened", n, "limit", limit)
+				idea.DescriptionFit = FitSummarized
+				return
+			}
+			slog.WarnContext(ctx, "Shortened description is still too long, truncating it", "length", n, "limit", limit)
+		}
+	}
+	idea.Description = textfit.Truncate(idea.Description, limit)
+	idea.DescriptionFit = FitTruncated
+}
 
-	return &IssueIdea{
-		Title:       title,
-		Description: description,
-	}, nil
+// shortenDescription asks the generation model to summarise description in
+// language, using the shorten template for projectKey.
+func shortenDescription(ctx context.Context, projectKey, description, language string) (summary, templateID string, err error) {
+	prompt, templateID, err := RenderPrompt(prompts.Shorten, projectKey, description, language)
+	if err != nil {
+		return "", "", err
+	}
+	cfg := settings().Ollama
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	answer, err := callOllama(ctx, cfg.Model, "shorten", prompt)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(answer), templateID, nil
+}
+
+// ParseIssue extracts the title and description from a model answer in the
+// "Title: ... Description: ..." format, before any length limits are applied.
+func ParseIssue(content string) (title, description string) {
+	return extractTitle(content), extractDescription(content)
 }
 
 func extractTitle(content string) string {
@@ -221,10 +388,8 @@ func extractDescription(content string) string {
 	return "No description provided"
 }
 
+// sanitizeTitle puts title on one line within the configured limit.
 func sanitizeTitle(title string) string {
 	title = strings.ReplaceAll(title, "\n", " ")
-	if len(title) > 255 {
-		title = title[:255]
-	}
-	return title
+	return textfit.Truncate(title, settings().Limits.TitleMaxLength)
 }
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const mask = "[REDACTED]"
+
+// credentialPatterns mask credentials wherever they appear.
+var credentialPatterns = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), "$1 " + mask},
+	{regexp.MustCompile(`\bATATT[A-Za-z0-9_=+/-]+`), mask},
+	{regexp.MustCompile(`(?i)\b(api[_-]?token|access[_-]?token|password|secret)(["']?\s*[:=]\s*["']?)[^\s"',}]+`), "$1$2" + mask},
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// sensitiveKeys are attribute keys whose values are always masked.
+var sensitiveKeys = []string{"token", "password", "secret", "authorization", "api_key", "apikey"}
+
+// Redactor masks secrets and PII in log messages and attribute values.
+type Redactor struct {
+	extra []*regexp.Regexp
+}
+
+// NewRedactor builds a redactor with the built-in rules plus patterns, each a
+// regular expression whose matches are replaced with [REDACTED].
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.extra = append(r.extra, re)
+	}
+	return r, nil
+}
+
+// String returns s with credentials, email addresses and the extra
+// patterns masked.
+func (r *Redactor) String(s string) string {
+	for _, p := range credentialPatterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	s = emailPattern.ReplaceAllString(s, "[EMAIL]")
+	return r.replaceExtra(s)
+}
+
+// Credentials is String without the email rule, for text that must keep
+// personal data intact, such as recorded responses replayed later.
+func (r *Redactor) Credentials(s string) string {
+	for _, p := range credentialPatterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return r.replaceExtra(s)
+}
+
+func (r *Redactor) replaceExtra(s string) string {
+	for _, re := range r.extra {
+		s = re.ReplaceAllString(s, mask)
+	}
+	return s
+}
+
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
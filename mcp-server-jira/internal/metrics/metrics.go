@@ -0,0 +1,139 @@
+// Package metrics defines the Prometheus metrics exported by mcp-server-jira.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	rpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "RPCs completed on the server, by method and status code.",
+	}, []string{"method", "code"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time taken to handle an RPC, by method.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"method"})
+
+	llmDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "llm_request_duration_seconds",
+		Help:    "Latency of LLM calls, by model and operation.",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	}, []string{"model", "operation"})
+
+	llmTokens = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_tokens_total",
+		Help: "Tokens processed by the LLM, by model and type (prompt or completion).",
+	}, []string{"model", "type"})
+
+	jiraRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_api_requests_total",
+		Help: "Jira REST API calls, by operation and HTTP status (or \"error\").",
+	}, []string{"operation", "status"})
+
+	routingDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "routing_decisions_total",
+		Help: "Message routing decisions, by intent (or \"clarify\").",
+	}, []string{"intent"})
+
+	routingConfidence = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "routing_confidence",
+		Help:    "Similarity of routed messages to their best intent.",
+		Buckets: prometheus.LinearBuckets(0.1, 0.1, 9),
+	})
+
+	guardRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "guard_rejections_total",
+		Help: "Requests rejected by the prompt guard, by stage (input or output) and rule.",
+	}, []string{"stage", "rule"})
+
+	httpRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbound_http_retries_total",
+		Help: "Retried outbound HTTP requests, by dependency.",
+	}, []string{"dependency"})
+
+	breakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "Circuit breaker state by dependency: 0 closed, 1 half-open, 2 open.",
+	}, []string{"dependency"})
+)
+
+// UnaryServerInterceptor records latency and status codes for every RPC.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	rpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	rpcHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+	return resp, err
+}
+
+// ObserveLLM records one LLM call and the tokens it consumed.
+func ObserveLLM(model, operation string, elapsed time.Duration, promptTokens, completionTokens int) {
+	llmDuration.WithLabelValues(model, operation).Observe(elapsed.Seconds())
+	llmTokens.WithLabelValues(model, "prompt").Add(float64(promptTokens))
+	llmTokens.WithLabelValues(model, "completion").Add(float64(completionTokens))
+}
+
+// ObserveJira records a Jira API call. statusCode 0 means the request failed
+// before a response was received.
+func ObserveJira(operation string, statusCode int) {
+	label := "error"
+	if statusCode != 0 {
+		label = strconv.Itoa(statusCode)
+	}
+	jiraRequests.WithLabelValues(operation, label).Inc()
+}
+
+// ObserveRouting records the intent a message was routed to, or "clarify",
+// and the router's confidence in its best match.
+func ObserveRouting(intent string, confidence float64) {
+	routingDecisions.WithLabelValues(intent).Inc()
+	routingConfidence.Observe(confidence)
+}
+
+// ObserveRejection records a request rejected by the prompt guard.
+func ObserveRejection(stage, rule string) {
+	guardRejections.WithLabelValues(stage, rule).Inc()
+}
+
+// ObserveRetry records a retried request to dependency.
+func ObserveRetry(dependency string) {
+	httpRetries.WithLabelValues(dependency).Inc()
+}
+
+// SetBreakerState publishes the circuit breaker state for dependency.
+func SetBreakerState(dependency string, state int) {
+	breakerState.WithLabelValues(dependency).Set(float64(state))
+}
+
+// Serve exposes /metrics on addr until ctx is cancelled.
+func Serve(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Serving metrics", "addr", addr, "path", "/metrics")
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Metrics server failed", "error", err)
+	}
+}
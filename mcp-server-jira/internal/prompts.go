@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"sync/atomic"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/guard"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/lang"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+)
+
+var promptSet atomic.Pointer[prompts.Set]
+
+// UsePrompts renders every subsequent LLM prompt from set. A nil set means
+// the built-in templates.
+func UsePrompts(set *prompts.Set) {
+	promptSet.Store(set)
+}
+
+func currentPrompts() *prompts.Set {
+	if set := promptSet.Load(); set != nil {
+		return set
+	}
+	return prompts.Builtin()
+}
+
+// RenderPrompt returns the prompt that would be sent to the model for the
+// named template, project and user message, along with the template ID that
+// is recorded with generated cards. For the shorten template the message is
+// the description to shorten, and for jql the search question. language
+// overrides the configured issue language when not empty.
+func RenderPrompt(name, projectKey, message, language string) (prompt, templateID string, err error) {
+	t, err := currentPrompts().Lookup(name, projectKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	var data any
+	switch name {
+	case prompts.Generate:
+		cfg := settings()
+		target, source := issueLanguage(projectKey, language, message)
+		gen := prompts.GenerateData{
+			Project:              projectKey,
+			DataInstruction:      guard.DataInstruction,
+			UserMessage:          guard.Delimit(message),
+			TitleMaxLength:       cfg.Limits.TitleMaxLength,
+			DescriptionMaxLength: cfg.Limits.DescriptionMaxLength,
+		}
+		gen.Language, _ = lang.Name(target)
+		if cfg.Language.Bilingual && source != target {
+			gen.SecondLanguage, _ = lang.Name(source)
+		}
+		data = gen
+	case prompts.Shorten:
+		cfg := settings()
+		target, _ := issueLanguage(projectKey, language, message)
+		short := prompts.ShortenData{
+			Project:         projectKey,
+			DataInstruction: guard.DataInstruction,
+			Description:     guard.Delimit(message),
+			MaxLength:       cfg.Limits.DescriptionMaxLength,
+		}
+		short.Language, _ = lang.Name(target)
+		data = short
+	case prompts.JQL:
+		data = prompts.JQLData{
+			Project:         projectKey,
+			DataInstruction: guard.DataInstruction,
+			UserMessage:     guard.Delimit(message),
+		}
+	}
+
+	prompt, err = t.Render(data)
+	if err != nil {
+		return "", "", err
+	}
+	return prompt, t.ID(), nil
+}
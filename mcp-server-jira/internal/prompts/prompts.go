@@ -0,0 +1,243 @@
+// Package prompts loads the text/template prompts sent to the LLM. Built-in
+// defaults are embedded in the binary; a prompts directory may replace any of
+// them, and <dir>/projects/<KEY>/ overrides them for a single Jira project.
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/validate"
+)
+
+// Template names. Messages are routed by package intent rather than by a
+// prompt.
+const (
+	// Generate writes an issue from the user's request.
+	Generate = "generate"
+	// Shorten summarises a generated description that is over the limit.
+	Shorten = "shorten"
+	// JQL translates a search question into a JQL query.
+	JQL = "jql"
+)
+
+// Names lists every template, in the order the server uses them.
+var Names = []string{Generate, Shorten, JQL}
+
+// GenerateData is the input to the generate template.
+type GenerateData struct {
+	Project         string
+	DataInstruction string
+	// UserMessage is the user's message, already wrapped in delimiter tags.
+	UserMessage          string
+	TitleMaxLength       int
+	DescriptionMaxLength int
+	// Language is the English name of the language to write the issue in,
+	// such as "Thai".
+	Language string
+	// SecondLanguage, when set, is a language the description should be
+	// repeated in.
+	SecondLanguage string
+}
+
+// ShortenData is the input to the shorten template.
+type ShortenData struct {
+	Project         string
+	DataInstruction string
+	// Description is the over-long description, already wrapped in
+	// delimiter tags.
+	Description string
+	MaxLength   int
+	// Language is the English name of the language the description is in.
+	Language string
+}
+
+// JQLData is the input to the jql template.
+type JQLData struct {
+	// Project is the project to search when the question names none.
+	Project         string
+	DataInstruction string
+	// UserMessage is the question, already wrapped in delimiter tags.
+	UserMessage string
+}
+
+// sampleData is rendered once per template at load time so that a typo in a
+// field name fails the load instead of a request.
+var sampleData = map[string]any{
+	Generate: GenerateData{Project: "AIT", UserMessage: "<user_message>\nhello\n</user_message>", TitleMaxLength: 255, DescriptionMaxLength: 1000, Language: "English", SecondLanguage: "Thai"},
+	Shorten:  ShortenData{Project: "AIT", Description: "<user_message>\nhello\n</user_message>", MaxLength: 1000, Language: "English"},
+	JQL:      JQLData{Project: "AIT", UserMessage: "<user_message>\nmy open bugs\n</user_message>"},
+}
+
+//go:embed defaults/*.tmpl
+var defaults embed.FS
+
+var versionPattern = regexp.MustCompile(`^\{\{/\*\s*version:\s*([A-Za-z0-9._-]+)\s*\*/`)
+
+// Template is one parsed prompt.
+type Template struct {
+	Name string
+	// Version is declared by a leading {{/* version: X */}} comment, or
+	// derived from the content when the template doesn't declare one.
+	Version string
+	// Source is the file the template came from, or "builtin".
+	Source string
+	tmpl   *template.Template
+}
+
+// ID identifies the template and version, e.g. "generate@3". It is recorded
+// with every generated card.
+func (t *Template) ID() string {
+	return t.Name + "@" + t.Version
+}
+
+// Render executes the template with data.
+func (t *Template) Render(data any) (string, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render %s prompt: %w", t.Name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Set is a loaded collection of templates with per-project overrides.
+type Set struct {
+	base     map[string]*Template
+	projects map[string]map[string]*Template
+}
+
+// Lookup returns the named template for project, falling back to the
+// directory-wide and then the built-in template.
+func (s *Set) Lookup(name, project string) (*Template, error) {
+	if t, ok := s.projects[project][name]; ok {
+		return t, nil
+	}
+	if t, ok := s.base[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown prompt template %q", name)
+}
+
+var builtin = mustLoadBuiltin()
+
+// Builtin returns the templates embedded in the binary.
+func Builtin() *Set {
+	return builtin
+}
+
+func mustLoadBuiltin() *Set {
+	s := &Set{base: make(map[string]*Template)}
+	for _, name := range Names {
+		text, err := defaults.ReadFile("defaults/" + name + ".tmpl")
+		if err != nil {
+			panic(err)
+		}
+		t, err := parse(name, "builtin", string(text))
+		if err != nil {
+			panic(err)
+		}
+		s.base[name] = t
+	}
+	return s
+}
+
+// Load reads <name>.tmpl files from dir and from dir/projects/<KEY>/ on top
+// of the built-in templates. An empty dir returns the built-in set. Every
+// template is test-rendered, so a broken one fails here rather than at
+// request time.
+func Load(dir string) (*Set, error) {
+	if dir == "" {
+		return builtin, nil
+	}
+	s := &Set{base: make(map[string]*Template), projects: make(map[string]map[string]*Template)}
+	for name, t := range builtin.base {
+		s.base[name] = t
+	}
+
+	overrides, err := loadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for name, t := range overrides {
+		s.base[name] = t
+	}
+
+	projectsDir := filepath.Join(dir, "projects")
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read prompts directory: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if !validate.IsProjectKey(e.Name()) {
+			return nil, fmt.Errorf("%s: %q is not a Jira project key", projectsDir, e.Name())
+		}
+		overrides, err := loadDir(filepath.Join(projectsDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if len(overrides) > 0 {
+			s.projects[e.Name()] = overrides
+		}
+	}
+	return s, nil
+}
+
+func loadDir(dir string) (map[string]*Template, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("read prompts directory: %w", err)
+		}
+	}
+	templates := make(map[string]*Template)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		if _, ok := sampleData[name]; !ok {
+			return nil, fmt.Errorf("%s: unknown prompt template %q (expected one of %s)", file, name, strings.Join(Names, ", "))
+		}
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read prompt template: %w", err)
+		}
+		t, err := parse(name, file, string(text))
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
+
+func parse(name, source, text string) (*Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	t := &Template{Name: name, Source: source, tmpl: tmpl}
+	if m := versionPattern.FindStringSubmatch(text); m != nil {
+		t.Version = m[1]
+	} else {
+		sum := sha256.Sum256([]byte(text))
+		t.Version = "sha-" + hex.EncodeToString(sum[:4])
+	}
+	if _, err := t.Render(sampleData[name]); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return t, nil
+}
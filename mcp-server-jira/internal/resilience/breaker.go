@@ -0,0 +1,119 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+)
+
+var (
+	// ErrCircuitOpen is returned without contacting a dependency whose
+	// breaker has tripped.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+	// ErrUnavailable is wrapped by errors for 429/502/503/504 responses that
+	// were still failing after the last attempt.
+	ErrUnavailable = errors.New("dependency unavailable")
+)
+
+// State is a circuit breaker state. The values are exported as the
+// circuit_breaker_state metric.
+type State int
+
+const (
+	Closed State = iota
+	HalfOpen
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// breaker opens after threshold consecutive failures and, once cooldown has
+// passed, lets a single probe through to decide whether to close again.
+type breaker struct {
+	name string
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(name string) *breaker {
+	metrics.SetBreakerState(name, int(Closed))
+	return &breaker{name: name}
+}
+
+// allow reports whether a request may be sent at now.
+func (b *breaker) allow(now time.Time, cooldown time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if now.Sub(b.openedAt) < cooldown {
+			return ErrCircuitOpen
+		}
+		b.setState(HalfOpen)
+		b.probing = true
+		return nil
+	case HalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record feeds the outcome of a request allowed by allow, finished at now,
+// back into b.
+func (b *breaker) record(now time.Time, ok bool, threshold int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if ok {
+		b.failures = 0
+		b.setState(Closed)
+		return
+	}
+
+	b.failures++
+	if b.state == HalfOpen || (threshold > 0 && b.failures >= threshold) {
+		b.openedAt = now
+		b.setState(Open)
+	}
+}
+
+// release ends a request allowed by allow without judging the dependency,
+// e.g. because the caller cancelled it.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) current() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *breaker) setState(s State) {
+	if b.state != s {
+		b.state = s
+		metrics.SetBreakerState(b.name, int(s))
+	}
+}
@@ -0,0 +1,223 @@
+// Package resilience wraps outbound HTTP calls to a dependency with retries,
+// exponential backoff with jitter and a circuit breaker.
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+)
+
+// Policy controls retries and the circuit breaker. MaxAttempts of 1
+// disables retries; BreakerThreshold of 0 disables the breaker.
+type Policy struct {
+	MaxAttempts      int
+	InitialBackoff   time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultPolicy is used until SetPolicy is called.
+var DefaultPolicy = Policy{
+	MaxAttempts:      3,
+	InitialBackoff:   200 * time.Millisecond,
+	MaxBackoff:       5 * time.Second,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// Client sends requests to one named dependency. Breaker state survives
+// policy changes, so a config reload does not close an open circuit.
+type Client struct {
+	name    string
+	http    *http.Client
+	policy  atomic.Pointer[Policy]
+	breaker *breaker
+
+	// now, sleep and jitter are replaced in tests to control time.
+	now    func() time.Time
+	sleep  func(context.Context, time.Duration) error
+	jitter func(time.Duration) time.Duration
+}
+
+// New returns a Client for dependency name that sends requests with hc.
+func New(name string, hc *http.Client) *Client {
+	c := &Client{
+		name:    name,
+		http:    hc,
+		breaker: newBreaker(name),
+		now:     time.Now,
+		sleep:   sleep,
+		jitter:  rand.N[time.Duration],
+	}
+	c.SetPolicy(DefaultPolicy)
+	return c
+}
+
+// SetPolicy replaces the policy for subsequent requests.
+func (c *Client) SetPolicy(p Policy) {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	c.policy.Store(&p)
+}
+
+// State returns the current circuit breaker state.
+func (c *Client) State() State {
+	return c.breaker.current()
+}
+
+// Do sends req, retrying transport errors and 429/502/503/504 responses when
+// the method is idempotent (GET, HEAD, OPTIONS, PUT or DELETE). A response
+// with one of those statuses that is still failing when Do gives up is
+// returned as an error wrapping ErrUnavailable.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.do(req, idempotentMethod(req.Method))
+}
+
+// DoIdempotent is Do for a request the caller knows is safe to repeat even
+// though its method is not, such as an LLM generation.
+func (c *Client) DoIdempotent(req *http.Request) (*http.Response, error) {
+	return c.do(req, true)
+}
+
+func (c *Client) do(req *http.Request, retry bool) (*http.Response, error) {
+	p := *c.policy.Load()
+	attempts := p.MaxAttempts
+	if !retry || (req.Body != nil && req.GetBody == nil) {
+		attempts = 1
+	}
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if err := c.breaker.allow(c.now(), p.BreakerCooldown); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up; that says nothing about the dependency.
+			c.breaker.release()
+			return nil, err
+		}
+		failed := err != nil || retryableStatus(resp.StatusCode)
+		c.breaker.record(c.now(), !failed, p.BreakerThreshold)
+		if !failed {
+			return resp, nil
+		}
+		if attempt >= attempts {
+			return c.giveUp(resp, err)
+		}
+
+		wait := c.backoff(p, attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), c.now()); ok {
+				// Waiting longer than the policy allows, or past the
+				// caller's deadline, only delays the same failure.
+				if after > p.MaxBackoff {
+					return c.giveUp(resp, nil)
+				}
+				wait = after
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(c.now()) < wait {
+			return c.giveUp(resp, err)
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		reason := "transport error"
+		if err == nil {
+			reason = resp.Status
+		}
+		slog.WarnContext(ctx, "Retrying request", "dependency", c.name, "attempt", attempt, "reason", reason, "wait", wait)
+		metrics.ObserveRetry(c.name)
+
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) giveUp(resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return nil, fmt.Errorf("%s returned %s: %w", c.name, resp.Status, ErrUnavailable)
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns a full-jitter exponential delay before retry attempt+1.
+func (c *Client) backoff(p Policy, attempt int) time.Duration {
+	limit := p.InitialBackoff << (attempt - 1)
+	if limit <= 0 || limit > p.MaxBackoff {
+		limit = p.MaxBackoff
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return c.jitter(limit) + 1
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+// relative to now.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,349 @@
+package resilience
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// epoch is where every fakeClock starts.
+var epoch = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeClock stands in for the wall clock: sleeping advances it instantly
+// and jitter always picks the largest delay.
+type fakeClock struct {
+	mu     sync.Mutex
+	t      time.Time
+	waits  []time.Duration
+	limits []time.Duration
+}
+
+func (f *fakeClock) now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.t
+}
+
+func (f *fakeClock) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.t = f.t.Add(d)
+}
+
+func (f *fakeClock) sleep(_ context.Context, d time.Duration) error {
+	f.mu.Lock()
+	f.waits = append(f.waits, d)
+	f.mu.Unlock()
+	f.advance(d)
+	return nil
+}
+
+func (f *fakeClock) jitter(limit time.Duration) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.limits = append(f.limits, limit)
+	return limit - 1
+}
+
+// script answers each request with the next response in turn, repeating the
+// last one, and counts requests and the bodies they carried.
+type script struct {
+	mu        sync.Mutex
+	responses []func(http.ResponseWriter)
+	bodies    []string
+}
+
+func (s *script) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.bodies = append(s.bodies, string(body))
+	respond := s.responses[min(len(s.bodies), len(s.responses))-1]
+	s.mu.Unlock()
+	respond(w)
+}
+
+func (s *script) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+func status(code int) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) { w.WriteHeader(code) }
+}
+
+func retryAfterStatus(code int, value string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", value)
+		w.WriteHeader(code)
+	}
+}
+
+func newTestClient(t *testing.T, p Policy, responses ...func(http.ResponseWriter)) (*Client, *script, *fakeClock, string) {
+	t.Helper()
+	s := &script{responses: responses}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	clock := &fakeClock{t: epoch}
+	c := New("test", srv.Client())
+	c.now, c.sleep, c.jitter = clock.now, clock.sleep, clock.jitter
+	c.SetPolicy(p)
+	return c, s, clock, srv.URL
+}
+
+func get(t *testing.T, c *Client, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestBackoffGrowsAndCaps(t *testing.T) {
+	p := Policy{MaxAttempts: 6, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	c, srv, clock, url := newTestClient(t, p, status(http.StatusServiceUnavailable))
+
+	if _, err := get(t, c, url); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v, want ErrUnavailable", err)
+	}
+	if n := srv.calls(); n != 6 {
+		t.Errorf("sent %d requests, want 6", n)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, limit := range clock.limits {
+		if limit != want[i] {
+			t.Errorf("jitter limit before retry %d = %v, want %v", i+1, limit, want[i])
+		}
+		if clock.waits[i] != want[i] {
+			t.Errorf("wait before retry %d = %v, want %v", i+1, clock.waits[i], want[i])
+		}
+	}
+	if len(clock.limits) != len(want) {
+		t.Errorf("backed off %d times, want %d", len(clock.limits), len(want))
+	}
+}
+
+func TestBackoffJitterStaysInRange(t *testing.T) {
+	c := New("test", http.DefaultClient)
+	p := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt := 1; attempt <= 8; attempt++ {
+		limit := min(p.InitialBackoff<<(attempt-1), p.MaxBackoff)
+		for range 200 {
+			if d := c.backoff(p, attempt); d <= 0 || d > limit {
+				t.Fatalf("backoff(attempt %d) = %v, want (0, %v]", attempt, d, limit)
+			}
+		}
+	}
+	if d := c.backoff(Policy{}, 1); d != 0 {
+		t.Errorf("backoff with no delay configured = %v, want 0", d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	p := Policy{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+	t.Run("seconds", func(t *testing.T) {
+		c, srv, clock, url := newTestClient(t, p, retryAfterStatus(http.StatusTooManyRequests, "2"), status(http.StatusOK))
+		if _, err := get(t, c, url); err != nil {
+			t.Fatal(err)
+		}
+		if srv.calls() != 2 || len(clock.waits) != 1 || clock.waits[0] != 2*time.Second {
+			t.Errorf("calls %d, waits %v; want 2 calls after waiting 2s", srv.calls(), clock.waits)
+		}
+	})
+
+	t.Run("http date", func(t *testing.T) {
+		at := epoch.Add(3 * time.Second).Format(http.TimeFormat)
+		c, _, clock, url := newTestClient(t, p, retryAfterStatus(http.StatusServiceUnavailable, at), status(http.StatusOK))
+		if _, err := get(t, c, url); err != nil {
+			t.Fatal(err)
+		}
+		if len(clock.waits) != 1 || clock.waits[0] != 3*time.Second {
+			t.Errorf("waits %v, want [3s]", clock.waits)
+		}
+	})
+
+	t.Run("beyond max backoff", func(t *testing.T) {
+		c, srv, clock, url := newTestClient(t, p, retryAfterStatus(http.StatusServiceUnavailable, "60"))
+		if _, err := get(t, c, url); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("got %v, want ErrUnavailable", err)
+		}
+		if srv.calls() != 1 || len(clock.waits) != 0 {
+			t.Errorf("calls %d, waits %v; want one call and no wait", srv.calls(), clock.waits)
+		}
+	})
+
+	for _, value := range []string{"", "soon", "-1"} {
+		if _, ok := retryAfter(value, time.Now()); ok {
+			t.Errorf("retryAfter(%q) parsed", value)
+		}
+	}
+}
+
+func TestRetriesOnlyIdempotentMethods(t *testing.T) {
+	p := Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		forceSafe bool
+		want      int
+	}{
+		{"GET", http.MethodGet, "", false, 3},
+		{"PUT", http.MethodPut, "{}", false, 3},
+		{"DELETE", http.MethodDelete, "", false, 3},
+		{"POST", http.MethodPost, `{"fields":{}}`, false, 1},
+		{"PATCH", http.MethodPatch, "{}", false, 1},
+		{"POST marked idempotent", http.MethodPost, `{"prompt":"hi"}`, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv, _, url := newTestClient(t, p, status(http.StatusBadGateway))
+			req, err := http.NewRequest(tt.method, url, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			do := c.Do
+			if tt.forceSafe {
+				do = c.DoIdempotent
+			}
+			if _, err := do(req); !errors.Is(err, ErrUnavailable) {
+				t.Fatalf("got %v, want ErrUnavailable", err)
+			}
+			if n := srv.calls(); n != tt.want {
+				t.Errorf("sent %d requests, want %d", n, tt.want)
+			}
+			for i, body := range srv.bodies {
+				if body != tt.body {
+					t.Errorf("attempt %d sent body %q, want %q", i+1, body, tt.body)
+				}
+			}
+		})
+	}
+}
+
+func TestNoRetryPastDeadline(t *testing.T) {
+	p := Policy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	c, srv, clock, url := newTestClient(t, p, status(http.StatusServiceUnavailable))
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute))
+	defer cancel()
+	clock.t = time.Now()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v, want ErrUnavailable", err)
+	}
+	if srv.calls() != 1 || len(clock.waits) != 0 {
+		t.Errorf("calls %d, waits %v; want one call and no wait", srv.calls(), clock.waits)
+	}
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	p := Policy{MaxAttempts: 1, BreakerThreshold: 3, BreakerCooldown: 30 * time.Second}
+	c, srv, clock, url := newTestClient(t, p,
+		status(http.StatusServiceUnavailable),
+		status(http.StatusServiceUnavailable),
+		status(http.StatusServiceUnavailable),
+		status(http.StatusServiceUnavailable), // failed half-open probe
+		status(http.StatusOK),
+	)
+
+	for i := 1; i <= 3; i++ {
+		if c.State() != Closed {
+			t.Fatalf("state before failure %d = %v, want closed", i, c.State())
+		}
+		get(t, c, url)
+	}
+	if c.State() != Open {
+		t.Fatalf("state after 3 failures = %v, want open", c.State())
+	}
+	if _, err := get(t, c, url); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("open breaker: got %v, want ErrCircuitOpen", err)
+	}
+	if n := srv.calls(); n != 3 {
+		t.Errorf("open breaker let a request through: %d calls", n)
+	}
+
+	clock.advance(29 * time.Second)
+	if _, err := get(t, c, url); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("before cooldown: got %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown one probe is let through; its failure reopens the
+	// breaker for another full cooldown.
+	clock.advance(time.Second)
+	if _, err := get(t, c, url); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("failed probe: got %v, want ErrUnavailable", err)
+	}
+	if c.State() != Open {
+		t.Fatalf("state after failed probe = %v, want open", c.State())
+	}
+	clock.advance(29 * time.Second)
+	if _, err := get(t, c, url); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("cooldown restarted by failed probe: got %v, want ErrCircuitOpen", err)
+	}
+
+	clock.advance(time.Second)
+	if _, err := get(t, c, url); err != nil {
+		t.Errorf("successful probe: %v", err)
+	}
+	if c.State() != Closed {
+		t.Errorf("state after successful probe = %v, want closed", c.State())
+	}
+	if n := srv.calls(); n != 5 {
+		t.Errorf("sent %d requests, want 5", n)
+	}
+}
+
+func TestBreakerHalfOpenAllowsOneProbe(t *testing.T) {
+	b := newBreaker("test")
+	b.record(epoch, false, 1)
+	if b.current() != Open {
+		t.Fatalf("state = %v, want open", b.current())
+	}
+
+	later := epoch.Add(time.Minute)
+	if err := b.allow(later, time.Second); err != nil {
+		t.Fatalf("probe after cooldown: %v", err)
+	}
+	if b.current() != HalfOpen {
+		t.Errorf("state during probe = %v, want half-open", b.current())
+	}
+	if err := b.allow(later, time.Second); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second request during probe: got %v, want ErrCircuitOpen", err)
+	}
+
+	// A cancelled probe says nothing about the dependency; the next request
+	// may probe again.
+	b.release()
+	if err := b.allow(later, time.Second); err != nil {
+		t.Errorf("probe after release: %v", err)
+	}
+	b.record(later, true, 1)
+	if b.current() != Closed {
+		t.Errorf("state after successful probe = %v, want closed", b.current())
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	p := Policy{MaxAttempts: 1}
+	c, _, _, url := newTestClient(t, p, status(http.StatusServiceUnavailable))
+	for range 10 {
+		get(t, c, url)
+	}
+	if c.State() != Closed {
+		t.Errorf("breaker with threshold 0 = %v, want closed", c.State())
+	}
+}
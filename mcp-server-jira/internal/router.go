@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/cassette"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/intent"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
+)
+
+var classifier atomic.Pointer[intent.Classifier]
+
+var builtinClassifier = intent.NewClassifier(intent.Builtin())
+
+// UseIntents classifies every subsequent message against set. A nil set
+// means the built-in intents.
+func UseIntents(set *intent.Set) {
+	if set == nil {
+		classifier.Store(nil)
+		return
+	}
+	classifier.Store(intent.NewClassifier(set))
+}
+
+func currentClassifier() *intent.Classifier {
+	if c := classifier.Load(); c != nil {
+		return c
+	}
+	return builtinClassifier
+}
+
+// CurrentIntents returns the intents messages are classified into.
+func CurrentIntents() *intent.Set {
+	return currentClassifier().Set()
+}
+
+// ClassifyMessage embeds prompt and picks the closest intent, or
+// intent.Clarify when the router is not confident enough to act.
+func ClassifyMessage(ctx context.Context, prompt string) (intent.Result, error) {
+	cfg := settings()
+	ctx, span := tracing.Tracer().Start(ctx, "ClassifyMessage", trace.WithAttributes(
+		attribute.String("llm.model", cfg.Ollama.EmbeddingModel),
+	))
+	defer span.End()
+
+	c := currentClassifier()
+	if cassette.Active(ctx) {
+		// A cassette must hold the example embeddings too, or a replay in a
+		// fresh process would have nothing to answer them with.
+		c = intent.NewClassifier(c.Set())
+	}
+	result, err := c.Classify(ctx, embed, cfg.Ollama.EmbeddingModel, prompt, intent.Thresholds{
+		MinConfidence: cfg.Router.MinConfidence,
+		MinMargin:     cfg.Router.MinMargin,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return intent.Result{}, err
+	}
+	span.SetAttributes(
+		attribute.String("routing.intent", result.Intent),
+		attribute.Float64("routing.confidence", result.Confidence),
+	)
+	slog.DebugContext(ctx, "Intent scores", "scores", result.Scores)
+	if result.Intent == intent.Clarify {
+		slog.InfoContext(ctx, "Intent unclear, asking for clarification", "guess", result.Guess, "confidence", result.Confidence)
+	} else {
+		slog.InfoContext(ctx, "Classified message", "intent", result.Intent, "confidence", result.Confidence)
+	}
+	metrics.ObserveRouting(result.Intent, result.Confidence)
+	return result, nil
+}
+
+// embed returns the embedding of each text from Ollama's /api/embed.
+func embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	cfg := settings().Ollama
+	payload, err := json.Marshal(map[string]any{
+		"model": model,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.BaseURL+"/api/embed", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	var promptTokens int
+	defer func() {
+		metrics.ObserveLLM(model, "embed", time.Since(start), promptTokens, 0)
+	}()
+
+	resp, err := ollamaHTTP.DoIdempotent(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to Ollama failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError("ollama", "embed", resp, bodyBytes)
+	}
+
+	var result struct {
+		Embeddings      [][]float64 `json:"embeddings"`
+		PromptEvalCount int         `json:"prompt_eval_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings: %w", err)
+	}
+	promptTokens = result.PromptEvalCount
+	return result.Embeddings, nil
+}
+
+// clarification asks the user which of the supported actions they meant.
+func clarification(set *intent.Set, result intent.Result) string {
+	var actions []string
+	for _, in := range set.Intents {
+		if in.Name != intent.ChitChat {
+			actions = append(actions, in.Description)
+		}
+	}
+	msg := "I'm not sure what you'd like me to do."
+	if len(actions) > 0 {
+		msg += " I can " + joinOr(actions) + "."
+	}
+	if guess, ok := set.Lookup(result.Guess); ok && guess.Name != intent.ChitChat {
+		msg += fmt.Sprintf(" Did you want to %s?", guess.Description)
+	}
+	return msg
+}
+
+func joinOr(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
@@ -0,0 +1,333 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/status"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/metrics"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/prompts"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
+)
+
+// DefaultSearchFields are returned when a search names no fields.
+var DefaultSearchFields = []string{"summary", "status", "assignee", "updated"}
+
+// DefaultSearchResults is the page size when a search asks for none.
+const DefaultSearchResults = 50
+
+// SearchQuery is an issue search. Exactly one of JQL and Question is set;
+// ProjectKey is the project a question is about when it names none.
+type SearchQuery struct {
+	JQL        string
+	Question   string
+	ProjectKey string
+	StartAt    int
+	MaxResults int
+	Fields     []string
+}
+
+// SearchResult is one page of issues and the JQL that found them.
+type SearchResult struct {
+	JQL     string
+	Issues  []Issue
+	Total   int
+	StartAt int
+}
+
+// Issue is a Jira issue as read from the REST API. A search fills in only
+// the fields it asked for.
+type Issue struct {
+	Key         string
+	Summary     string
+	Status      string
+	IssueType   string
+	Assignee    string
+	Reporter    string
+	Priority    string
+	Labels      []string
+	Created     time.Time
+	Updated     time.Time
+	Description string
+}
+
+// InvalidJQLError is a question the model could not turn into a query
+// Jira accepts. JQL is empty when the model returned no query at all.
+type InvalidJQLError struct {
+	JQL      string
+	Messages []string
+}
+
+func (e *InvalidJQLError) Error() string {
+	return fmt.Sprintf("invalid JQL %q: %s", e.JQL, strings.Join(e.Messages, "; "))
+}
+
+// GRPCStatus reports the query and Jira's objections as a violation of the
+// question field.
+func (e *InvalidJQLError) GRPCStatus() *status.Status {
+	if e.JQL == "" {
+		return InvalidArgumentStatus("question", "could not be translated to JQL")
+	}
+	return InvalidArgumentStatus("question", fmt.Sprintf("was translated to %q, which Jira rejected: %s", e.JQL, strings.Join(e.Messages, "; ")))
+}
+
+// SearchIssues runs q as the caller identified by creds. A question is
+// screened by the prompt guard, translated to JQL by the model and checked
+// by Jira before the search runs.
+func SearchIssues(ctx context.Context, creds *Credentials, q SearchQuery) (*SearchResult, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SearchIssues")
+	defer span.End()
+
+	if creds == nil {
+		return nil, ErrNoCredentials
+	}
+	jql := q.JQL
+	if q.Question != "" {
+		projectKey := q.ProjectKey
+		if projectKey == "" {
+			projectKey = settings().Jira.ProjectKey
+		}
+		if err := ScreenPrompt(ctx, creds, projectKey, q.Question); err != nil {
+			return nil, err
+		}
+		var err error
+		if jql, err = TranslateJQL(ctx, projectKey, q.Question); err != nil {
+			return nil, err
+		}
+		if err := checkJQL(ctx, creds, jql); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+	span.SetAttributes(attribute.String("jira.jql", jql))
+
+	fields := q.Fields
+	if len(fields) == 0 {
+		fields = DefaultSearchFields
+	}
+	maxResults := q.MaxResults
+	if maxResults == 0 {
+		maxResults = DefaultSearchResults
+	}
+	body, err := json.Marshal(map[string]any{
+		"jql":        jql,
+		"startAt":    q.StartAt,
+		"maxResults": maxResults,
+		"fields":     fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search: %w", err)
+	}
+
+	var page struct {
+		StartAt int           `json:"startAt"`
+		Total   int           `json:"total"`
+		Issues  []issueFields `json:"issues"`
+	}
+	if err := jiraRead(ctx, creds, "search", http.MethodPost, "/rest/api/2/search", body, &page); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := &SearchResult{JQL: jql, Total: page.Total, StartAt: page.StartAt}
+	for _, is := range page.Issues {
+		result.Issues = append(result.Issues, is.issue())
+	}
+	span.SetAttributes(attribute.Int("jira.total", page.Total))
+	slog.InfoContext(ctx, "Searched Jira", "jql", jql, "total", page.Total, "returned", len(result.Issues))
+	return result, nil
+}
+
+// TranslateJQL asks the generation model for a JQL query answering
+// question, using the jql template for projectKey.
+func TranslateJQL(ctx context.Context, projectKey, question string) (string, error) {
+	prompt, templateID, err := RenderPrompt(prompts.JQL, projectKey, question, "")
+	if err != nil {
+		return "", err
+	}
+	cfg := settings().Ollama
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	answer, err := callOllama(ctx, cfg.Model, "jql", prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to translate question to JQL: %w", err)
+	}
+	jql := cleanJQL(answer)
+	slog.InfoContext(ctx, "Translated question to JQL", "jql", jql, "prompt_template", templateID)
+	if jql == "" {
+		return "", &InvalidJQLError{Messages: []string{"the model returned no query"}}
+	}
+	return jql, nil
+}
+
+var (
+	codeFence = regexp.MustCompile("(?s)^```[a-zA-Z]*\\s*(.*?)\\s*```")
+	jqlLabel  = regexp.MustCompile(`(?i)^jql\s*:\s*`)
+)
+
+// cleanJQL extracts the query from a model answer that may wrap it in a
+// code fence, a "JQL:" label or backticks, or spread it over several lines.
+func cleanJQL(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if m := codeFence.FindStringSubmatch(answer); m != nil {
+		answer = m[1]
+	}
+	answer = jqlLabel.ReplaceAllString(answer, "")
+	answer = strings.Trim(answer, "`")
+	return strings.Join(strings.Fields(answer), " ")
+}
+
+// checkJQL asks Jira to parse jql strictly, so a query the model got wrong
+// is reported as such rather than as a failed search.
+func checkJQL(ctx context.Context, creds *Credentials, jql string) error {
+	body, err := json.Marshal(map[string]any{"queries": []string{jql}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JQL: %w", err)
+	}
+	var parsed struct {
+		Queries []struct {
+			Errors []string `json:"errors"`
+		} `json:"queries"`
+	}
+	if err := jiraRead(ctx, creds, "parse_jql", http.MethodPost, "/rest/api/2/jql/parse?validation=strict", body, &parsed); err != nil {
+		return err
+	}
+	if len(parsed.Queries) == 1 && len(parsed.Queries[0].Errors) > 0 {
+		return &InvalidJQLError{JQL: jql, Messages: parsed.Queries[0].Errors}
+	}
+	return nil
+}
+
+// jiraRead sends a request that changes nothing in Jira, retrying it like
+// any idempotent call, and decodes the JSON response into out.
+func jiraRead(ctx context.Context, creds *Credentials, operation, method, path string, body []byte, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, creds.BaseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", creds.Authorization())
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := jiraHTTP.DoIdempotent(req)
+	if err != nil {
+		metrics.ObserveJira(operation, 0)
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+	metrics.ObserveJira(operation, resp.StatusCode)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		slog.WarnContext(ctx, "Jira API rejected request", "operation", operation, "status", resp.Status, "body", string(respBody))
+		return newAPIError("jira", operation, resp, respBody)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", operation, err)
+	}
+	return nil
+}
+
+// jiraTimeFormat is the timestamp format of Jira REST responses.
+const jiraTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+// issueFields is an issue as returned by the Jira REST API v2.
+type issueFields struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string    `json:"summary"`
+		Description string    `json:"description"`
+		Status      *named    `json:"status"`
+		IssueType   *named    `json:"issuetype"`
+		Priority    *named    `json:"priority"`
+		Assignee    *jiraUser `json:"assignee"`
+		Reporter    *jiraUser `json:"reporter"`
+		Labels      []string  `json:"labels"`
+		Created     jiraTime  `json:"created"`
+		Updated     jiraTime  `json:"updated"`
+		Comment     struct {
+			Comments []jiraComment `json:"comments"`
+		} `json:"comment"`
+		IssueLinks []jiraLink    `json:"issuelinks"`
+		Subtasks   []issueFields `json:"subtasks"`
+		Parent     *issueFields  `json:"parent"`
+	} `json:"fields"`
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+type jiraUser struct {
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+func (u *jiraUser) String() string {
+	if u == nil {
+		return ""
+	}
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.EmailAddress
+}
+
+func (n *named) String() string {
+	if n == nil {
+		return ""
+	}
+	return n.Name
+}
+
+// jiraTime is a Jira timestamp; an empty or missing one is the zero time.
+type jiraTime struct{ time.Time }
+
+func (t *jiraTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil || s == "" {
+		return err
+	}
+	parsed, err := time.Parse(jiraTimeFormat, s)
+	if err != nil {
+		return fmt.Errorf("unexpected Jira timestamp %q", s)
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (is issueFields) issue() Issue {
+	f := is.Fields
+	return Issue{
+		Key:         is.Key,
+		Summary:     f.Summary,
+		Status:      f.Status.String(),
+		IssueType:   f.IssueType.String(),
+		Assignee:    f.Assignee.String(),
+		Reporter:    f.Reporter.String(),
+		Priority:    f.Priority.String(),
+		Labels:      f.Labels,
+		Created:     f.Created.Time,
+		Updated:     f.Updated.Time,
+		Description: f.Description,
+	}
+}
@@ -0,0 +1,239 @@
+// Package secrets keeps credentials such as the Jira API token in a local
+// file encrypted with AES-256-GCM.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// KeyEnv holds the store key itself; it is never read from a config file.
+	KeyEnv  = "MCP_SECRETS_KEY"
+	keySize = 32
+)
+
+var (
+	ErrNoKey    = fmt.Errorf("no secrets key configured: set %s or a key file", KeyEnv)
+	ErrNotFound = errors.New("secret not found")
+)
+
+type envelope struct {
+	Version    int    `json:"version"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Store is an encrypted name → value map persisted to a single file.
+type Store struct {
+	mu     sync.RWMutex
+	path   string
+	key    []byte
+	values map[string]string
+}
+
+// GenerateKey returns a new random base64-encoded key.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// LoadKey reads the store key from MCP_SECRETS_KEY or, failing that, keyFile.
+func LoadKey(keyFile string) ([]byte, error) {
+	encoded := os.Getenv(KeyEnv)
+	if encoded == "" {
+		path := keyFile
+		if path == "" {
+			return nil, ErrNoKey
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		encoded = string(data)
+	}
+	return DecodeKey(encoded)
+}
+
+// Configured reports whether a store key has been provided.
+func Configured(keyFile string) bool {
+	return os.Getenv(KeyEnv) != "" || keyFile != ""
+}
+
+// DecodeKey parses a base64-encoded store key.
+func DecodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
+
+// Open decrypts the store at path. A missing file yields an empty store that
+// is created on the first Save.
+func Open(path string, key []byte) (*Store, error) {
+	s := &Store{path: path, key: key, values: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file (wrong key?): %w", err)
+	}
+	if err := json.Unmarshal(plaintext, &s.values); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted secrets: %w", err)
+	}
+	return s, nil
+}
+
+// OpenWithKeyFile opens the store at path using the key from LoadKey.
+func OpenWithKeyFile(path, keyFile string) (*Store, error) {
+	key, err := LoadKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return Open(path, key)
+}
+
+func (s *Store) Get(name string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+func (s *Store) Set(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+}
+
+// List returns the stored secret names in sorted order.
+func (s *Store) List() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.values))
+	for name := range s.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save encrypts the store and atomically replaces the file on disk.
+func (s *Store) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.save()
+}
+
+// Rotate re-encrypts the store under newKey.
+func (s *Store) Rotate(newKey []byte) error {
+	if len(newKey) != keySize {
+		return fmt.Errorf("key must be %d bytes, got %d", keySize, len(newKey))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	oldKey := s.key
+	s.key = newKey
+	if err := s.save(); err != nil {
+		s.key = oldKey
+		return err
+	}
+	return nil
+}
+
+func (s *Store) save() error {
+	plaintext, err := json.Marshal(s.values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	data, err := json.Marshal(envelope{
+		Version:    1,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets file: %w", err)
+	}
+	return WriteFileAtomic(s.path, data)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
+
+// WriteFileAtomic writes data to a temporary file readable only by the owner
+// and renames it over path.
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	encoded, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := DecodeKey(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	key := newKey(t)
+
+	s, err := Open(path, key)
+	if err != nil {
+		t.Fatalf("Open missing file: %v", err)
+	}
+	s.Set("JIRA_EMAIL", "bot@example.com")
+	s.Set("JIRA_API_TOKEN", "ATATT-secret")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ATATT-secret") || strings.Contains(string(data), "bot@example.com") {
+		t.Error("store file contains a plaintext secret")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("store file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	s, err = Open(path, key)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, _ := s.Get("JIRA_API_TOKEN"); got != "ATATT-secret" {
+		t.Errorf("JIRA_API_TOKEN = %q", got)
+	}
+	if names := s.List(); len(names) != 2 || names[0] != "JIRA_API_TOKEN" || names[1] != "JIRA_EMAIL" {
+		t.Errorf("List() = %v", names)
+	}
+	if _, err := s.Get("JIRA_OAUTH_TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	s, _ := Open(path, newKey(t))
+	s.Set("JIRA_API_TOKEN", "ATATT-secret")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, newKey(t)); err == nil {
+		t.Error("Open with the wrong key succeeded")
+	}
+}
+
+func TestTamperedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	key := newKey(t)
+	s, _ := Open(path, key)
+	s.Set("JIRA_API_TOKEN", "ATATT-secret")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flip one bit of the ciphertext; GCM must refuse to decrypt it.
+	env := readEnvelope(t, path)
+	env.Ciphertext[0] ^= 1
+	writeEnvelope(t, path, env)
+	if _, err := Open(path, key); err == nil {
+		t.Error("Open of a tampered file succeeded")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	oldKey, rotatedKey := newKey(t), newKey(t)
+	s, _ := Open(path, oldKey)
+	s.Set("JIRA_API_TOKEN", "ATATT-secret")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Rotate(rotatedKey); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if _, err := Open(path, oldKey); err == nil {
+		t.Error("old key still opens the store after rotation")
+	}
+	rotated, err := Open(path, rotatedKey)
+	if err != nil {
+		t.Fatalf("Open with new key: %v", err)
+	}
+	if got, _ := rotated.Get("JIRA_API_TOKEN"); got != "ATATT-secret" {
+		t.Errorf("JIRA_API_TOKEN after rotation = %q", got)
+	}
+
+	if err := s.Rotate([]byte("short")); err == nil {
+		t.Error("Rotate accepted a short key")
+	}
+	if _, err := Open(path, rotatedKey); err != nil {
+		t.Errorf("failed rotation changed the store: %v", err)
+	}
+}
+
+func TestDecodeKey(t *testing.T) {
+	for _, encoded := range []string{"", "not base64!", "c2hvcnQ="} {
+		if _, err := DecodeKey(encoded); err == nil {
+			t.Errorf("DecodeKey(%q) succeeded", encoded)
+		}
+	}
+}
+
+func readEnvelope(t *testing.T, path string) envelope {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func writeEnvelope(t *testing.T, path string, env envelope) {
+	t.Helper()
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"sync/atomic"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/cassette"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/config"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/resilience"
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/tracing"
+)
+
+var current atomic.Pointer[config.Config]
+
+// Outbound clients for each dependency. They live for the whole process so
+// circuit breaker state carries across config reloads. Requests made while
+// recording or replaying a cassette go through the cassette transport.
+var (
+	jiraHTTP   = resilience.New("jira", cassette.Wrap("jira", tracing.HTTPClient()))
+	ollamaHTTP = resilience.New("ollama", cassette.Wrap("ollama", tracing.HTTPClient()))
+)
+
+func init() {
+	Configure(config.Default())
+}
+
+// Configure replaces the settings used for all subsequent LLM and Jira calls.
+func Configure(cfg *config.Config) {
+	policy := resilience.Policy{
+		MaxAttempts:      cfg.Resilience.MaxAttempts,
+		InitialBackoff:   cfg.Resilience.InitialBackoff,
+		MaxBackoff:       cfg.Resilience.MaxBackoff,
+		BreakerThreshold: cfg.Resilience.BreakerFailures,
+		BreakerCooldown:  cfg.Resilience.BreakerCooldown,
+	}
+	jiraHTTP.SetPolicy(policy)
+	ollamaHTTP.SetPolicy(policy)
+	current.Store(cfg)
+}
+
+// CurrentConfig returns the settings most recently passed to Configure.
+func CurrentConfig() *config.Config {
+	return current.Load()
+}
+
+func settings() *config.Config {
+	return current.Load()
+}
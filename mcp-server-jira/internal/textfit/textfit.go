@@ -0,0 +1,90 @@
+// Package textfit fits generated text into the length limits of Jira fields.
+// Limits count characters, not bytes, and text is only ever cut between
+// characters a reader sees as one: a Thai consonant keeps its vowel and tone
+// marks, and an emoji keeps its modifiers and joined parts.
+package textfit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ellipsis marks text that was cut short.
+const Ellipsis = "…"
+
+// Len returns the length of s in characters, the unit field limits are in.
+func Len(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// Truncate returns s if it fits in max characters. Otherwise it cuts s so
+// that, with Ellipsis appended, it fits: at the last whitespace in the
+// second half of the allowance, or else at the last boundary between
+// characters. A max below 2 leaves no room for the marker, so the text is
+// cut without one. Invalid UTF-8 in s is replaced with U+FFFD.
+func Truncate(s string, max int) string {
+	s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	if Len(s) <= max {
+		return s
+	}
+	if max <= 0 {
+		return ""
+	}
+	marker := Ellipsis
+	if max < 2 {
+		marker = ""
+	}
+	runes := []rune(s)
+	budget := max - Len(marker)
+
+	cut := budget
+	for i := budget; i > budget/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	for cut > 0 && continuesCluster(runes, cut) {
+		cut--
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + marker
+}
+
+const (
+	zeroWidthJoiner = '\u200d'
+	regionalA       = '\U0001f1e6'
+	regionalZ       = '\U0001f1ff'
+	skinToneLight   = '\U0001f3fb'
+	skinToneDark    = '\U0001f3ff'
+)
+
+// continuesCluster reports whether runes[i] belongs to the same
+// user-perceived character as runes[i-1], so the text can't be cut between
+// them.
+func continuesCluster(runes []rune, i int) bool {
+	r, prev := runes[i], runes[i-1]
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		// Combining marks, including Thai vowel and tone marks and the
+		// emoji variation selector.
+		return true
+	case r == zeroWidthJoiner || prev == zeroWidthJoiner:
+		return true
+	case r >= skinToneLight && r <= skinToneDark:
+		return true
+	case isRegional(r) && isRegional(prev):
+		// Flags are pairs of regional indicators; only an even number
+		// before i puts i at the start of a flag.
+		n := 0
+		for j := i - 1; j >= 0 && isRegional(runes[j]); j-- {
+			n++
+		}
+		return n%2 == 1
+	}
+	return false
+}
+
+func isRegional(r rune) bool {
+	return r >= regionalA && r <= regionalZ
+}
@@ -0,0 +1,37 @@
+package textfit
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"Fix login", 20, "Fix login"},
+		{"Fix login", 9, "Fix login"},
+		{"Fix the login page on Safari", 20, "Fix the login page…"},
+		{"Supercalifragilistic", 10, "Supercali…"},
+		{"แก้ไขหน้าเข้าสู่ระบบ", 8, "แก้ไขห…"},
+		{"ทดสอบ ระบบเข้าสู่ระบบใหม่", 10, "ทดสอบ…"},
+		{"กี่วัน", 4, "กี่…"},
+		{"กี่วัน", 3, "…"},
+		{"Deploy 👍🏽 now", 9, "Deploy…"},
+		{"Team 👨‍👩‍👧 offsite", 10, "Team…"},
+		{"Go 🇹🇭🇯🇵", 6, "Go 🇹🇭…"},
+		{"Fix login", 1, "F"},
+		{"Fix login", 0, ""},
+		{"bad \xff\xfe bytes here", 8, "bad �…"},
+	} {
+		got := Truncate(tc.text, tc.max)
+		if got != tc.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tc.text, tc.max, got, tc.want)
+		}
+		if !utf8.ValidString(got) || Len(got) > max(tc.max, 0) {
+			t.Errorf("Truncate(%q, %d) = %q: invalid UTF-8 or over the limit", tc.text, tc.max, got)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package tlsconfig builds the TLS settings for the JiraService gRPC server
+// and generates certificates for local development.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/cuenobi/mcp-platform/shared/tlsclient"
+)
+
+// Files names the PEM files used by the server. When ClientCAFile is set,
+// clients must present a certificate signed by it (mutual TLS).
+type Files struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+// Enabled reports whether a server certificate has been configured.
+func (f Files) Enabled() bool {
+	return f.CertFile != "" || f.KeyFile != ""
+}
+
+// MutualTLS reports whether client certificates are required.
+func (f Files) MutualTLS() bool {
+	return f.ClientCAFile != ""
+}
+
+// Server loads the certificate pair and, for mutual TLS, the client CA pool.
+func Server(f Files) (*tls.Config, error) {
+	if f.CertFile == "" || f.KeyFile == "" {
+		return nil, fmt.Errorf("both TLS cert and key files are required")
+	}
+	cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if f.MutualTLS() {
+		pool, err := tlsclient.LoadCertPool(f.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
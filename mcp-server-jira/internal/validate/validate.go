@@ -0,0 +1,176 @@
+// Package validate checks incoming RPC requests before any LLM or Jira call
+// is made. The rules are documented on the fields in jira.proto.
+package validate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cuenobi/mcp-platform/mcp-server-jira/internal/lang"
+	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxIdempotencyKeyLength bounds CreateCardRequest.idempotency_key.
+const MaxIdempotencyKeyLength = 255
+
+// MaxSearchResults bounds SearchIssuesRequest.max_results.
+const MaxSearchResults = 100
+
+// IssueFields are the fields SearchIssuesRequest.fields may name, in the
+// order they are displayed.
+var IssueFields = []string{"summary", "status", "issuetype", "assignee", "reporter", "priority", "labels", "created", "updated", "description"}
+
+var (
+	projectKeyPattern     = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,9}$`)
+	issueKeyPattern       = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,9}-[1-9][0-9]*$`)
+	idempotencyKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+)
+
+// Rules holds the configurable limits.
+type Rules struct {
+	// PromptMaxLength is the longest accepted prompt, in characters.
+	PromptMaxLength int
+}
+
+// IsProjectKey reports whether key looks like a Jira project key: an
+// uppercase letter followed by 1-9 uppercase letters, digits or underscores.
+func IsProjectKey(key string) bool {
+	return projectKeyPattern.MatchString(key)
+}
+
+// UnaryServerInterceptor rejects invalid requests with InvalidArgument and a
+// google.rpc.BadRequest listing every violation. rules is called per request
+// so reloaded limits apply immediately.
+func UnaryServerInterceptor(rules func() Rules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := Request(req, rules()); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Request validates a JiraService request message. Unknown messages pass.
+func Request(req any, r Rules) error {
+	var v violations
+	switch req := req.(type) {
+	case *pb.SyncRequest:
+		v.projectKey("project_key", req.ProjectKey)
+	case *pb.CreateCardRequest:
+		v.projectKey("project_key", req.ProjectKey)
+		v.prompt("prompt", req.Prompt, r.PromptMaxLength)
+		v.idempotencyKey("idempotency_key", req.IdempotencyKey)
+		v.language("language", req.Language)
+	case *pb.MessageRequest:
+		v.prompt("prompt", req.Prompt, r.PromptMaxLength)
+	case *pb.SearchIssuesRequest:
+		switch {
+		case req.Jql != "" && req.Question != "":
+			v.add("jql", "must not be set together with question")
+		case req.Question != "":
+			v.prompt("question", req.Question, r.PromptMaxLength)
+		case strings.TrimSpace(req.Jql) == "":
+			v.add("jql", "is required unless question is set")
+		case !utf8.ValidString(req.Jql):
+			v.add("jql", "must be valid UTF-8")
+		}
+		if req.ProjectKey != "" {
+			v.projectKey("project_key", req.ProjectKey)
+		}
+		if req.StartAt < 0 {
+			v.add("start_at", "must not be negative")
+		}
+		if req.MaxResults < 0 || req.MaxResults > MaxSearchResults {
+			v.add("max_results", "must be between 1 and %d, or 0 for the default", MaxSearchResults)
+		}
+		for _, f := range req.Fields {
+			if !slices.Contains(IssueFields, f) {
+				v.add("fields", "%q is not a field (expected any of %s)", f, strings.Join(IssueFields, ", "))
+			}
+		}
+	case *pb.GetIssueRequest:
+		switch {
+		case req.IssueKey == "":
+			v.add("issue_key", "is required")
+		case !issueKeyPattern.MatchString(req.IssueKey):
+			v.add("issue_key", "%q is not a Jira issue key (a project key, a hyphen and a number, such as AIT-123)", req.IssueKey)
+		}
+	}
+	return v.err()
+}
+
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *violations) projectKey(field, key string) {
+	switch {
+	case key == "":
+		v.add(field, "is required")
+	case !IsProjectKey(key):
+		v.add(field, "%q is not a Jira project key (2-10 characters: an uppercase letter, then uppercase letters, digits or underscores)", key)
+	}
+}
+
+func (v *violations) prompt(field, prompt string, maxLength int) {
+	switch {
+	case strings.TrimSpace(prompt) == "":
+		v.add(field, "is required")
+	case !utf8.ValidString(prompt):
+		v.add(field, "must be valid UTF-8")
+	case maxLength > 0 && utf8.RuneCountInString(prompt) > maxLength:
+		v.add(field, "must be at most %d characters, got %d", maxLength, utf8.RuneCountInString(prompt))
+	default:
+		for _, r := range prompt {
+			if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+				v.add(field, "must not contain control character %U", r)
+				return
+			}
+		}
+	}
+}
+
+func (v *violations) idempotencyKey(field, key string) {
+	switch {
+	case key == "":
+	case len(key) > MaxIdempotencyKeyLength:
+		v.add(field, "must be at most %d bytes", MaxIdempotencyKeyLength)
+	case !idempotencyKeyPattern.MatchString(key):
+		v.add(field, "may only contain letters, digits, '.', '_', ':' and '-'")
+	}
+}
+
+func (v *violations) language(field, code string) {
+	if _, ok := lang.Name(code); !ok && code != "" {
+		v.add(field, "%q is not a supported language (expected one of %s)", code, strings.Join(lang.Codes(), ", "))
+	}
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	fields := make([]string, len(v))
+	for i, fv := range v {
+		fields[i] = fv.Field
+	}
+	st := status.New(codes.InvalidArgument, "invalid "+strings.Join(fields, ", "))
+	if withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
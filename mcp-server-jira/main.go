@@ -2,15 +2,36 @@ package main
 
 import (
 	"log"
+	"os"
+	"slices"
 
 	"github.com/cuenobi/mcp-platform/mcp-server-jira/cmd"
+	jira "github.com/cuenobi/mcp-platform/mcp-server-jira/internal"
 	"github.com/joho/godotenv"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
+	loadDotEnv()
+	cmd.Execute()
+}
+
+// loadDotEnv copies settings from .env into the environment without
+// overriding variables that are already set. Credentials are skipped: they
+// belong in the secret store or the real environment, not in a plaintext
+// file next to the binary.
+func loadDotEnv() {
+	env, err := godotenv.Read()
+	if err != nil {
 		log.Println("Could not load .env file; using system environment")
+		return
+	}
+	for name, value := range env {
+		if slices.Contains(jira.CredentialSecrets, name) {
+			log.Printf("Ignoring %s in .env; store it with \"mcp-server-jira secrets set %s\"", name, name)
+			continue
+		}
+		if _, ok := os.LookupEnv(name); !ok {
+			os.Setenv(name, value)
+		}
 	}
-
-	cmd.Execute()
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/cuenobi/mcp-platform/mcphost/internal/jira"
+	"github.com/cuenobi/mcp-platform/mcphost/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+var metricsAddr string
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run as a long-lived process relaying stdin messages to the MCP server",
+	Long: `Run as a long-lived process. Each line read from stdin is sent to the MCP
+server as a message and the reply is printed. Prometheus metrics are served
+on --metrics-addr until SIGINT or SIGTERM, which also cancels the message in
+flight.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		if metricsAddr != "" {
+			go metrics.Serve(ctx, metricsAddr)
+		}
+
+		svc := jira.NewService()
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+				message, err := svc.Message(ctx, line)
+				if err != nil {
+					// One failed message does not stop the daemon.
+					fmt.Fprintf(os.Stderr, "error sending message: %s\n", jira.DescribeError(err))
+					continue
+				}
+				fmt.Printf("Message: %s\n", message)
+			}
+		}()
+
+		<-ctx.Done()
+		log.Println("mcphost daemon stopped")
+	},
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":9091", "Prometheus /metrics listen address (empty to disable)")
+	rootCmd.AddCommand(daemonCmd)
+}
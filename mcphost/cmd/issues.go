@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cuenobi/mcp-platform/mcphost/internal/jira"
+	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Output formats of the commands that read issues.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var searchFlags struct {
+	jql        string
+	fields     []string
+	startAt    int
+	maxResults int
+	output     string
+}
+
+var jiraSearchCmd = &cobra.Command{
+	Use:   "search [question]",
+	Short: "Search Jira issues with JQL or a plain-language question",
+	Long: `Search Jira issues. Either pass --jql, which runs as given, or ask a
+question in plain language, which the server translates to JQL and has Jira
+check before running it. The JQL that ran is printed above the results.`,
+	Example: `  mcphost jira search "my open bugs"
+  mcphost jira search -p OPS "what was updated this week?"
+  mcphost jira search --jql 'project = AIT AND status = "In Progress"' --fields summary,assignee
+  mcphost jira search --jql 'project = AIT' --start-at 50 --output json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case searchFlags.jql != "" && len(args) > 0:
+			return fmt.Errorf("pass either --jql or a question, not both")
+		case searchFlags.jql == "" && len(args) == 0:
+			return fmt.Errorf("pass a question or --jql")
+		}
+		return checkOutput(searchFlags.output)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Arguments are valid by now, so a failure is not a usage error.
+		cmd.SilenceUsage = true
+		svc := jira.NewService()
+		resp, err := svc.SearchIssues(cmd.Context(), jira.SearchQuery{
+			JQL:        searchFlags.jql,
+			Question:   strings.Join(args, " "),
+			Project:    project,
+			StartAt:    searchFlags.startAt,
+			MaxResults: searchFlags.maxResults,
+			Fields:     searchFlags.fields,
+		})
+		if err != nil {
+			return fmt.Errorf("searching issues: %s", jira.DescribeError(err))
+		}
+		if searchFlags.output == outputJSON {
+			printJSON(os.Stdout, resp)
+			return nil
+		}
+		printSearch(os.Stdout, resp, searchFlags.fields)
+		return nil
+	},
+}
+
+var showFlags struct {
+	output string
+}
+
+var jiraShowCmd = &cobra.Command{
+	Use:   "show <issue-key>",
+	Short: "Show a Jira issue with its comments, links and subtasks",
+	Example: `  mcphost jira show AIT-123
+  mcphost jira show ait-123 --output json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		return checkOutput(showFlags.output)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		svc := jira.NewService()
+		resp, err := svc.GetIssue(cmd.Context(), strings.ToUpper(args[0]))
+		if err != nil {
+			return fmt.Errorf("getting issue: %s", jira.DescribeError(err))
+		}
+		if showFlags.output == outputJSON {
+			printJSON(os.Stdout, resp)
+			return nil
+		}
+		printIssue(os.Stdout, resp)
+		return nil
+	},
+}
+
+func checkOutput(format string) error {
+	if format != outputTable && format != outputJSON {
+		return fmt.Errorf("--output must be %s or %s", outputTable, outputJSON)
+	}
+	return nil
+}
+
+func printJSON(w io.Writer, m proto.Message) {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		fmt.Fprintf(w, "error encoding response: %v\n", err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+// defaultSearchColumns matches the fields the server returns when a search
+// names none.
+var defaultSearchColumns = []string{"summary", "status", "assignee", "updated"}
+
+// printSearch renders one page of results as a table with a column per
+// field, followed by where the page sits in the results.
+func printSearch(w io.Writer, resp *pb.SearchIssuesResponse, fields []string) {
+	fmt.Fprintf(w, "JQL: %s\n\n", resp.Jql)
+	if len(resp.Issues) == 0 {
+		fmt.Fprintln(w, "No issues found.")
+		return
+	}
+	if len(fields) == 0 {
+		fields = defaultSearchColumns
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"KEY"}
+	for _, f := range fields {
+		header = append(header, strings.ToUpper(f))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, is := range resp.Issues {
+		row := []string{is.Key}
+		for _, f := range fields {
+			row = append(row, cell(issueField(is, f), 60))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+
+	first := int(resp.StartAt) + 1
+	last := int(resp.StartAt) + len(resp.Issues)
+	fmt.Fprintf(w, "\nShowing %d-%d of %d issues.", first, last, resp.Total)
+	if resp.NextStartAt > 0 {
+		fmt.Fprintf(w, " Next page: --start-at %d", resp.NextStartAt)
+	}
+	fmt.Fprintln(w)
+}
+
+// textWidth is the width descriptions and comments are wrapped to.
+const textWidth = 78
+
+// printIssue renders an issue for reading in a terminal: a heading, its
+// fields, the description, then subtasks, links and comments when there
+// are any.
+func printIssue(w io.Writer, resp *pb.GetIssueResponse) {
+	is := resp.Issue
+	fmt.Fprintf(w, "%s  %s\n", is.Key, is.Summary)
+	var kind []string
+	for _, s := range []string{is.IssueType, is.Status, is.Priority} {
+		if s != "" {
+			kind = append(kind, s)
+		}
+	}
+	fmt.Fprintf(w, "%s\n\n", strings.Join(kind, " · "))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Assignee:\t%s\n", orDash(is.Assignee))
+	fmt.Fprintf(tw, "Reporter:\t%s\n", orDash(is.Reporter))
+	if len(is.Labels) > 0 {
+		fmt.Fprintf(tw, "Labels:\t%s\n", strings.Join(is.Labels, ", "))
+	}
+	if resp.ParentKey != "" {
+		fmt.Fprintf(tw, "Parent:\t%s\n", resp.ParentKey)
+	}
+	fmt.Fprintf(tw, "Created:\t%s\n", date(is.Created))
+	fmt.Fprintf(tw, "Updated:\t%s\n", date(is.Updated))
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nDescription")
+	if strings.TrimSpace(is.Description) == "" {
+		fmt.Fprintln(w, "  (none)")
+	} else {
+		printText(w, is.Description, "  ")
+	}
+
+	if len(resp.Subtasks) > 0 {
+		fmt.Fprintf(w, "\nSubtasks (%d)\n", len(resp.Subtasks))
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range resp.Subtasks {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", sub.Key, sub.Status, cell(sub.Summary, 60))
+		}
+		tw.Flush()
+	}
+
+	if len(resp.Links) > 0 {
+		fmt.Fprintf(w, "\nLinks (%d)\n", len(resp.Links))
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, l := range resp.Links {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", l.Relation, l.Issue.Key, l.Issue.Status, cell(l.Issue.Summary, 50))
+		}
+		tw.Flush()
+	}
+
+	if len(resp.Comments) > 0 {
+		fmt.Fprintf(w, "\nComments (%d)\n", len(resp.Comments))
+		for i, c := range resp.Comments {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "  %s · %s\n", orDash(c.Author), date(c.Created))
+			printText(w, c.Body, "    ")
+		}
+	}
+}
+
+// printText writes text with each line indented by prefix and wrapped at
+// spaces to textWidth. Words longer than a line, and text written without
+// spaces such as Thai, are left on lines of their own.
+func printText(w io.Writer, text, prefix string) {
+	width := textWidth - utf8.RuneCountInString(prefix)
+	for _, para := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		para = strings.TrimRight(para, " \t\r")
+		if para == "" {
+			fmt.Fprintln(w)
+			continue
+		}
+		// Keep the paragraph's own indentation, as in lists and code.
+		lead := para[:len(para)-len(strings.TrimLeft(para, " \t"))]
+		var line string
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = lead + word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width:
+				fmt.Fprintln(w, prefix+line)
+				line = lead + word
+			default:
+				line += " " + word
+			}
+		}
+		fmt.Fprintln(w, prefix+line)
+	}
+}
+
+// issueField returns the named field of is as text.
+func issueField(is *pb.Issue, field string) string {
+	switch field {
+	case "summary":
+		return is.Summary
+	case "status":
+		return is.Status
+	case "issuetype":
+		return is.IssueType
+	case "assignee":
+		return orDash(is.Assignee)
+	case "reporter":
+		return orDash(is.Reporter)
+	case "priority":
+		return orDash(is.Priority)
+	case "labels":
+		return orDash(strings.Join(is.Labels, ", "))
+	case "created":
+		return date(is.Created)
+	case "updated":
+		return date(is.Updated)
+	case "description":
+		return is.Description
+	}
+	return ""
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// date shortens an RFC 3339 timestamp to its date and time.
+func date(ts string) string {
+	if len(ts) >= 16 {
+		return strings.Replace(ts[:16], "T", " ", 1)
+	}
+	return orDash(ts)
+}
+
+// cell puts s on one line and cuts it to max characters. It never cuts
+// inside a character a reader sees as one: a letter keeps its combining
+// marks, such as Thai vowel and tone marks, and an emoji keeps its
+// modifiers, joined parts and the other half of a flag.
+func cell(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	cut := max - 1
+	for cut > 0 && joinsPrevious(runes, cut) {
+		cut--
+	}
+	return string(runes[:cut]) + "…"
+}
+
+// joinsPrevious reports whether runes[i] is part of the same character as
+// runes[i-1].
+func joinsPrevious(runes []rune, i int) bool {
+	const zeroWidthJoiner = '\u200d'
+	r, prev := runes[i], runes[i-1]
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r == zeroWidthJoiner || prev == zeroWidthJoiner:
+		return true
+	case r >= '\U0001f3fb' && r <= '\U0001f3ff':
+		// Skin tone modifiers.
+		return true
+	case isRegional(r) && isRegional(prev):
+		// Flags are pairs of regional indicators.
+		n := 0
+		for j := i - 1; j >= 0 && isRegional(runes[j]); j-- {
+			n++
+		}
+		return n%2 == 1
+	}
+	return false
+}
+
+func isRegional(r rune) bool {
+	return r >= '\U0001f1e6' && r <= '\U0001f1ff'
+}
+
+func init() {
+	f := jiraSearchCmd.Flags()
+	f.StringVarP(&project, "project", "p", "", "Jira project a question is about (default $JIRA_PROJECT_KEY, then the server's default)")
+	f.StringVar(&searchFlags.jql, "jql", "", "JQL query to run instead of a question")
+	f.StringSliceVar(&searchFlags.fields, "fields", nil, "Fields to show: summary, status, issuetype, assignee, reporter, priority, labels, created, updated, description (default summary,status,assignee,updated)")
+	f.IntVar(&searchFlags.startAt, "start-at", 0, "Index of the first result, for paging")
+	f.IntVar(&searchFlags.maxResults, "limit", 0, "Results per page, at most 100 (default 50)")
+	f.StringVarP(&searchFlags.output, "output", "o", outputTable, "Output format: table or json")
+	jiraCmd.AddCommand(jiraSearchCmd)
+
+	jiraShowCmd.Flags().StringVarP(&showFlags.output, "output", "o", outputTable, "Output format: table or json")
+	jiraCmd.AddCommand(jiraShowCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import "testing"
+
+func TestCell(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"fits", "Add SSO login", 20, "Add SSO login"},
+		{"one line", "Add\nSSO\t login", 20, "Add SSO login"},
+		{"ascii", "Add SSO login for staff", 10, "Add SSO l…"},
+		{"thai", "แก้ไขหน้าเข้าสู่ระบบ", 6, "แก้ไข…"},
+		{"thai vowel and tone marks", "ที่นี่", 5, "ที่…"},
+		{"skin tone", "ab👍🏽cd", 4, "ab…"},
+		{"zwj sequence", "a👩‍💻b", 4, "a…"},
+		{"variation selector", "ab❤️cd", 4, "ab…"},
+		{"flag pair", "🇹🇭🇯🇵x", 4, "🇹🇭…"},
+		{"flag split", "a🇹🇭b", 3, "a…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cell(tt.in, tt.max); got != tt.want {
+				t.Errorf("cell(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+			}
+		})
+	}
+}
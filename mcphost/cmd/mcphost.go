@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/cuenobi/mcp-platform/mcphost/internal/jira"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var project string
@@ -17,44 +21,69 @@ var jiraCmd = &cobra.Command{
 var jiraSyncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Sync Jira issues",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
 		svc := jira.NewService()
-		if err := svc.Sync(project); err != nil {
-			fmt.Printf("error syncing jira: %v\n", err)
+		if err := svc.Sync(cmd.Context(), project); err != nil {
+			return fmt.Errorf("syncing jira: %s", jira.DescribeError(err))
 		}
+		return nil
 	},
 }
 
-var prompt string
+var (
+	prompt         string
+	language       string
+	idempotencyKey string
+)
 
 var jiraCreateCmd = &cobra.Command{
 	Use:   "create-card",
 	Short: "Create Jira issue from prompt",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		key := idempotencyKey
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+
 		svc := jira.NewService()
-		issueKey, err := svc.CreateCard(project, prompt)
+		issueKey, err := svc.CreateCard(cmd.Context(), project, prompt, language, key)
 		if err != nil {
-			fmt.Printf("error creating card: %v\n", err)
-			return
+			// Only failures that may have happened after Jira accepted the
+			// card are worth retrying with the same key.
+			switch status.Code(err) {
+			case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Internal, codes.Unknown:
+				return fmt.Errorf("creating card: %s\nRetry with --idempotency-key %s to avoid creating a duplicate", jira.DescribeError(err), key)
+			}
+			return fmt.Errorf("creating card: %s", jira.DescribeError(err))
 		}
 		fmt.Printf("Created issue: %s\n", issueKey)
+		return nil
 	},
 }
 
 var messageCmd = &cobra.Command{
 	Use:   "message",
 	Short: "Send message to MCP server",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
 		svc := jira.NewService()
-		message, err := svc.Message(prompt)
+		message, err := svc.Message(cmd.Context(), prompt)
 		if err != nil {
-			fmt.Printf("error sending message: %v\n", err)
-			return
+			return fmt.Errorf("sending message: %s", jira.DescribeError(err))
 		}
 		fmt.Printf("Message: %s\n", message)
+		return nil
 	},
 }
 
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 func init() {
 	jiraSyncCmd.Flags().StringVarP(&project, "project", "p", "", "Jira project key")
 	_ = jiraSyncCmd.MarkFlagRequired("project")
@@ -62,6 +91,8 @@ func init() {
 
 	jiraCreateCmd.Flags().StringVarP(&project, "project", "p", "", "Jira project key")
 	jiraCreateCmd.Flags().StringVarP(&prompt, "prompt", "", "", "Prompt to generate issue")
+	jiraCreateCmd.Flags().StringVar(&language, "language", "", "Language code for the issue, such as en or th (default: the server's setting for the project)")
+	jiraCreateCmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Reuse a key from a failed attempt so the card is created at most once (default: random)")
 	_ = jiraCreateCmd.MarkFlagRequired("project")
 	_ = jiraCreateCmd.MarkFlagRequired("prompt")
 
@@ -1,14 +1,47 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cuenobi/mcp-platform/mcphost/internal/tracing"
+	"github.com/spf13/cobra"
+)
+
+var shutdownTracing func(context.Context) error
 
 var rootCmd = &cobra.Command{
 	Use:   "mcphost",
 	Short: "MCPhost CLI",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		shutdown, err := tracing.Setup(context.Background(), "mcphost", tracing.OptionsFromEnv())
+		if err != nil {
+			return err
+		}
+		shutdownTracing = shutdown
+		return nil
+	},
 }
 
+// Execute runs the CLI. SIGINT and SIGTERM cancel the command context, which
+// aborts any RPC in flight.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	err := rootCmd.ExecuteContext(ctx)
+	// Flushed here rather than in a post-run hook, which cobra skips when a
+	// command fails, so the traces of failed calls are exported too.
+	if shutdownTracing != nil {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if ferr := shutdownTracing(flushCtx); ferr != nil {
+			log.Printf("failed to flush traces: %v", ferr)
+		}
+	}
+	return err
 }
 
 func init() {
@@ -7,35 +7,62 @@ import (
 	"os"
 	"time"
 
+	"github.com/cuenobi/mcp-platform/mcphost/internal/metrics"
 	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"github.com/cuenobi/mcp-platform/shared/tlsclient"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Client calls the Jira MCP server. Cancelling ctx aborts the RPC, and the
+// server stops generating and never writes to Jira for an aborted request.
 type Client interface {
-	Sync(project string) error
-	CreateCard(project, prompt string) (string, error)
-	Message(prompt string) (string, error)
+	Sync(ctx context.Context, project string) error
+	CreateCard(ctx context.Context, project, prompt, language, idempotencyKey string) (string, error)
+	Message(ctx context.Context, prompt string) (string, error)
+	SearchIssues(ctx context.Context, q SearchQuery) (*pb.SearchIssuesResponse, error)
+	GetIssue(ctx context.Context, key string) (*pb.GetIssueResponse, error)
+}
+
+// SearchQuery is an issue search: either JQL, run as given, or a
+// Question the server translates to JQL. Project scopes a question that
+// names none; Fields lists the issue fields to return.
+type SearchQuery struct {
+	JQL        string
+	Question   string
+	Project    string
+	StartAt    int
+	MaxResults int
+	Fields     []string
 }
 
 type grpcClient struct {
 	conn   *grpc.ClientConn
 	client pb.JiraServiceClient
+	creds  Credentials
 }
 
-func NewGRPCClient(addr string) Client {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewGRPCClient(addr string, creds Credentials, tlsCfg tlsclient.Config) Client {
+	transport, err := tlsCfg.DialOption()
+	if err != nil {
+		log.Fatalf("invalid TLS configuration: %v", err)
+	}
+	conn, err := grpc.Dial(addr, transport,
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor),
+	)
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
 	c := pb.NewJiraServiceClient(conn)
-	return &grpcClient{conn: conn, client: c}
+	return &grpcClient{conn: conn, client: c, creds: creds}
 }
 
-func (g *grpcClient) Sync(project string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (g *grpcClient) Sync(ctx context.Context, project string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx = g.creds.appendToContext(ctx)
 
 	_, err := g.client.SyncIssues(ctx, &pb.SyncRequest{
 		ProjectKey: project,
@@ -43,17 +70,20 @@ func (g *grpcClient) Sync(project string) error {
 	return err
 }
 
-func (g *grpcClient) CreateCard(project, prompt string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+func (g *grpcClient) CreateCard(ctx context.Context, project, prompt, language, idempotencyKey string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
+	ctx = g.creds.appendToContext(ctx)
 
 	if project == "" {
 		project = os.Getenv("JIRA_PROJECT_KEY")
 	}
 
 	resp, err := g.client.CreateCard(ctx, &pb.CreateCardRequest{
-		ProjectKey: project,
-		Prompt:     prompt,
+		ProjectKey:     project,
+		Prompt:         prompt,
+		Language:       language,
+		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		return "", err
@@ -64,9 +94,10 @@ func (g *grpcClient) CreateCard(project, prompt string) (string, error) {
 	return resp.IssueKey, nil
 }
 
-func (g *grpcClient) Message(prompt string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+func (g *grpcClient) Message(ctx context.Context, prompt string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
+	ctx = g.creds.appendToContext(ctx)
 
 	resp, err := g.client.Message(ctx, &pb.MessageRequest{
 		Prompt: prompt,
@@ -79,3 +110,45 @@ func (g *grpcClient) Message(prompt string) (string, error) {
 	}
 	return resp.Message, nil
 }
+
+func (g *grpcClient) SearchIssues(ctx context.Context, q SearchQuery) (*pb.SearchIssuesResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	ctx = g.creds.appendToContext(ctx)
+
+	project := q.Project
+	if project == "" && q.Question != "" {
+		project = os.Getenv("JIRA_PROJECT_KEY")
+	}
+
+	resp, err := g.client.SearchIssues(ctx, &pb.SearchIssuesRequest{
+		Jql:        q.JQL,
+		Question:   q.Question,
+		ProjectKey: project,
+		StartAt:    int32(q.StartAt),
+		MaxResults: int32(q.MaxResults),
+		Fields:     q.Fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("received nil response from server")
+	}
+	return resp, nil
+}
+
+func (g *grpcClient) GetIssue(ctx context.Context, key string) (*pb.GetIssueResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	ctx = g.creds.appendToContext(ctx)
+
+	resp, err := g.client.GetIssue(ctx, &pb.GetIssueRequest{IssueKey: key})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Issue == nil {
+		return nil, fmt.Errorf("received nil response from server")
+	}
+	return resp, nil
+}
@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"context"
+	"os"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Credentials is the caller's own Jira identity, forwarded to mcp-server-jira
+// so issues are created as this user rather than the shared service account.
+type Credentials struct {
+	Email      string
+	APIToken   string
+	OAuthToken string
+	CloudID    string
+}
+
+// CredentialsFromEnv reads the caller's Jira identity from JIRA_EMAIL and
+// JIRA_API_TOKEN, or from JIRA_OAUTH_TOKEN and JIRA_CLOUD_ID for OAuth 2.0 (3LO).
+func CredentialsFromEnv() Credentials {
+	return Credentials{
+		Email:      os.Getenv("JIRA_EMAIL"),
+		APIToken:   os.Getenv("JIRA_API_TOKEN"),
+		OAuthToken: os.Getenv("JIRA_OAUTH_TOKEN"),
+		CloudID:    os.Getenv("JIRA_CLOUD_ID"),
+	}
+}
+
+func (c Credentials) appendToContext(ctx context.Context) context.Context {
+	switch {
+	case c.OAuthToken != "":
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.OAuthToken)
+		if c.CloudID != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-jira-cloud-id", c.CloudID)
+		}
+	case c.Email != "" && c.APIToken != "":
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			"x-jira-email", c.Email,
+			"x-jira-api-token", c.APIToken,
+		)
+	}
+	return ctx
+}
@@ -1,6 +1,12 @@
 package jira
 
-import "os"
+import (
+	"context"
+	"os"
+
+	pb "github.com/cuenobi/mcp-platform/shared/proto/gen"
+	"github.com/cuenobi/mcp-platform/shared/tlsclient"
+)
 
 type Service struct {
 	client Client
@@ -13,22 +19,36 @@ func NewService() *Service {
 	}
 
 	return &Service{
-		client: NewGRPCClient(addr),
+		client: NewGRPCClient(addr, CredentialsFromEnv(), tlsclient.FromEnv()),
 	}
 }
 
-func (s *Service) Sync(project string) error {
-	return s.client.Sync(project)
+func (s *Service) Sync(ctx context.Context, project string) error {
+	return s.client.Sync(ctx, project)
 }
 
-func (s *Service) CreateCard(project, prompt string) (string, error) {
-	issueKey, err := s.client.CreateCard(project, prompt)
+// CreateCard asks the server to create an issue, written in language when
+// that is not empty. Calls with the same non-empty idempotencyKey create at
+// most one issue.
+func (s *Service) CreateCard(ctx context.Context, project, prompt, language, idempotencyKey string) (string, error) {
+	issueKey, err := s.client.CreateCard(ctx, project, prompt, language, idempotencyKey)
 	if err != nil {
 		return "", err
 	}
 	return issueKey, nil
 }
 
-func (s *Service) Message(prompt string) (string, error) {
-	return s.client.Message(prompt)
-}
\ No newline at end of file
+func (s *Service) Message(ctx context.Context, prompt string) (string, error) {
+	return s.client.Message(ctx, prompt)
+}
+
+// SearchIssues returns one page of issues matching q.
+func (s *Service) SearchIssues(ctx context.Context, q SearchQuery) (*pb.SearchIssuesResponse, error) {
+	return s.client.SearchIssues(ctx, q)
+}
+
+// GetIssue returns the issue with key, with its comments, links and
+// subtasks.
+func (s *Service) GetIssue(ctx context.Context, key string) (*pb.GetIssueResponse, error) {
+	return s.client.GetIssue(ctx, key)
+}
@@ -1,7 +1,14 @@
 package main
 
-import "github.com/cuenobi/mcp-platform/mcphost/cmd"
+import (
+	"os"
+
+	"github.com/cuenobi/mcp-platform/mcphost/cmd"
+)
 
 func main() {
-	cmd.Execute()
+	// cobra has already printed the error to stderr.
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -22,8 +22,10 @@ const (
 )
 
 type SyncRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectKey    string                 `protobuf:"bytes,1,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. A Jira project key: an uppercase letter followed by 1-9
+	// uppercase letters, digits or underscores.
+	ProjectKey    string `protobuf:"bytes,1,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,9 +68,22 @@ func (x *SyncRequest) GetProjectKey() string {
 }
 
 type CreateCardRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectKey    string                 `protobuf:"bytes,1,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	Prompt        string                 `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. Same format as SyncRequest.project_key.
+	ProjectKey string `protobuf:"bytes,1,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	// Required, non-blank, valid UTF-8 and at most limits.prompt_max_length
+	// characters (default 4000). Control characters other than tab, newline
+	// and carriage return are rejected.
+	Prompt string `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	// Optional client-chosen key. Retrying with the same key returns the
+	// original result instead of creating a second card. At most 255 bytes of
+	// letters, digits, '.', '_', ':' and '-'.
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Optional language code for the generated title and description: "en",
+	// "ja", "ko", "th" or "zh". Empty uses the server's language.projects
+	// entry for the project, then language.default, which may follow the
+	// language of the prompt.
+	Language      string `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,6 +132,20 @@ func (x *CreateCardRequest) GetPrompt() string {
 	return ""
 }
 
+func (x *CreateCardRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateCardRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
 type SyncResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -214,8 +243,9 @@ func (x *CreateCardResponse) GetStatus() string {
 }
 
 type MessageRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Prompt        string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. Same rules as CreateCardRequest.prompt.
+	Prompt        string `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -258,8 +288,14 @@ func (x *MessageRequest) GetPrompt() string {
 }
 
 type MessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// The intent the message was routed to, such as "create_issue" or
+	// "chit_chat". Empty when the router was unsure and message asks the user
+	// to clarify.
+	Intent string `protobuf:"bytes,2,opt,name=intent,proto3" json:"intent,omitempty"`
+	// Similarity of the message to its best intent, from 0 to 1.
+	Confidence    float64 `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -301,6 +337,575 @@ func (x *MessageResponse) GetMessage() string {
 	return ""
 }
 
+func (x *MessageResponse) GetIntent() string {
+	if x != nil {
+		return x.Intent
+	}
+	return ""
+}
+
+func (x *MessageResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+type SearchIssuesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Exactly one of jql and question is required. jql is run as given.
+	Jql string `protobuf:"bytes,1,opt,name=jql,proto3" json:"jql,omitempty"`
+	// A question in plain language, such as "my open bugs". The server's model
+	// translates it to JQL, which Jira validates before it runs. Same rules as
+	// CreateCardRequest.prompt.
+	Question string `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	// Optional project a question is about when it names none. Same format as
+	// SyncRequest.project_key; empty uses the server's jira.project_key.
+	// Ignored with jql.
+	ProjectKey string `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	// Index of the first result to return, for paging. Must not be negative.
+	StartAt int32 `protobuf:"varint,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	// Results per page, 1-100. 0 means 50.
+	MaxResults int32 `protobuf:"varint,5,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	// Fields to return with each key: any of "summary", "status",
+	// "issuetype", "assignee", "reporter", "priority", "labels", "created",
+	// "updated" and "description". Empty returns summary, status, assignee
+	// and updated.
+	Fields        []string `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchIssuesRequest) Reset() {
+	*x = SearchIssuesRequest{}
+	mi := &file_protos_jira_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchIssuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchIssuesRequest) ProtoMessage() {}
+
+func (x *SearchIssuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchIssuesRequest.ProtoReflect.Descriptor instead.
+func (*SearchIssuesRequest) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchIssuesRequest) GetJql() string {
+	if x != nil {
+		return x.Jql
+	}
+	return ""
+}
+
+func (x *SearchIssuesRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *SearchIssuesRequest) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
+}
+
+func (x *SearchIssuesRequest) GetStartAt() int32 {
+	if x != nil {
+		return x.StartAt
+	}
+	return 0
+}
+
+func (x *SearchIssuesRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+func (x *SearchIssuesRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SearchIssuesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The JQL that ran: jql as given, or the translation of question.
+	Jql    string   `protobuf:"bytes,1,opt,name=jql,proto3" json:"jql,omitempty"`
+	Issues []*Issue `protobuf:"bytes,2,rep,name=issues,proto3" json:"issues,omitempty"`
+	// Issues matching jql across all pages.
+	Total   int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	StartAt int32 `protobuf:"varint,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	// start_at of the next page, or 0 when this is the last page.
+	NextStartAt   int32 `protobuf:"varint,5,opt,name=next_start_at,json=nextStartAt,proto3" json:"next_start_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchIssuesResponse) Reset() {
+	*x = SearchIssuesResponse{}
+	mi := &file_protos_jira_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchIssuesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchIssuesResponse) ProtoMessage() {}
+
+func (x *SearchIssuesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchIssuesResponse.ProtoReflect.Descriptor instead.
+func (*SearchIssuesResponse) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchIssuesResponse) GetJql() string {
+	if x != nil {
+		return x.Jql
+	}
+	return ""
+}
+
+func (x *SearchIssuesResponse) GetIssues() []*Issue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+func (x *SearchIssuesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchIssuesResponse) GetStartAt() int32 {
+	if x != nil {
+		return x.StartAt
+	}
+	return 0
+}
+
+func (x *SearchIssuesResponse) GetNextStartAt() int32 {
+	if x != nil {
+		return x.NextStartAt
+	}
+	return 0
+}
+
+type GetIssueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. A Jira issue key: a project key, a hyphen and a number, such
+	// as "AIT-123".
+	IssueKey      string `protobuf:"bytes,1,opt,name=issue_key,json=issueKey,proto3" json:"issue_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIssueRequest) Reset() {
+	*x = GetIssueRequest{}
+	mi := &file_protos_jira_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIssueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIssueRequest) ProtoMessage() {}
+
+func (x *GetIssueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIssueRequest.ProtoReflect.Descriptor instead.
+func (*GetIssueRequest) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetIssueRequest) GetIssueKey() string {
+	if x != nil {
+		return x.IssueKey
+	}
+	return ""
+}
+
+type GetIssueResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Every field of the issue is filled in.
+	Issue *Issue `protobuf:"bytes,1,opt,name=issue,proto3" json:"issue,omitempty"`
+	// Oldest first.
+	Comments []*Comment   `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"`
+	Links    []*IssueLink `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	// Subtasks carry key, summary, status, issue_type and priority only.
+	Subtasks []*Issue `protobuf:"bytes,4,rep,name=subtasks,proto3" json:"subtasks,omitempty"`
+	// Key of the issue this is a subtask of, or empty.
+	ParentKey     string `protobuf:"bytes,5,opt,name=parent_key,json=parentKey,proto3" json:"parent_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIssueResponse) Reset() {
+	*x = GetIssueResponse{}
+	mi := &file_protos_jira_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIssueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIssueResponse) ProtoMessage() {}
+
+func (x *GetIssueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIssueResponse.ProtoReflect.Descriptor instead.
+func (*GetIssueResponse) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetIssueResponse) GetIssue() *Issue {
+	if x != nil {
+		return x.Issue
+	}
+	return nil
+}
+
+func (x *GetIssueResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *GetIssueResponse) GetLinks() []*IssueLink {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *GetIssueResponse) GetSubtasks() []*Issue {
+	if x != nil {
+		return x.Subtasks
+	}
+	return nil
+}
+
+func (x *GetIssueResponse) GetParentKey() string {
+	if x != nil {
+		return x.ParentKey
+	}
+	return ""
+}
+
+type Comment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Display name of the author.
+	Author string `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Body   string `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// RFC 3339 timestamp.
+	Created       string `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_protos_jira_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Comment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Comment) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+// IssueLink is a link between the issue and another one, described from the
+// issue's end, such as "blocks" or "is blocked by".
+type IssueLink struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Relation string                 `protobuf:"bytes,1,opt,name=relation,proto3" json:"relation,omitempty"`
+	// Carries key, summary, status, issue_type and priority only.
+	Issue         *Issue `protobuf:"bytes,2,opt,name=issue,proto3" json:"issue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueLink) Reset() {
+	*x = IssueLink{}
+	mi := &file_protos_jira_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueLink) ProtoMessage() {}
+
+func (x *IssueLink) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueLink.ProtoReflect.Descriptor instead.
+func (*IssueLink) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IssueLink) GetRelation() string {
+	if x != nil {
+		return x.Relation
+	}
+	return ""
+}
+
+func (x *IssueLink) GetIssue() *Issue {
+	if x != nil {
+		return x.Issue
+	}
+	return nil
+}
+
+// Issue is a Jira issue. Search results fill in key and the requested
+// fields only.
+type Issue struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Key       string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Summary   string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Status    string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	IssueType string                 `protobuf:"bytes,4,opt,name=issue_type,json=issueType,proto3" json:"issue_type,omitempty"`
+	// Display names; empty when unassigned.
+	Assignee string   `protobuf:"bytes,5,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	Reporter string   `protobuf:"bytes,6,opt,name=reporter,proto3" json:"reporter,omitempty"`
+	Priority string   `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Labels   []string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty"`
+	// RFC 3339 timestamps.
+	Created       string `protobuf:"bytes,9,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       string `protobuf:"bytes,10,opt,name=updated,proto3" json:"updated,omitempty"`
+	Description   string `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Issue) Reset() {
+	*x = Issue{}
+	mi := &file_protos_jira_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Issue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Issue) ProtoMessage() {}
+
+func (x *Issue) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_jira_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Issue.ProtoReflect.Descriptor instead.
+func (*Issue) Descriptor() ([]byte, []int) {
+	return file_protos_jira_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Issue) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Issue) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Issue) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Issue) GetIssueType() string {
+	if x != nil {
+		return x.IssueType
+	}
+	return ""
+}
+
+func (x *Issue) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *Issue) GetReporter() string {
+	if x != nil {
+		return x.Reporter
+	}
+	return ""
+}
+
+func (x *Issue) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *Issue) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Issue) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *Issue) GetUpdated() string {
+	if x != nil {
+		return x.Updated
+	}
+	return ""
+}
+
+func (x *Issue) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
 var File_protos_jira_proto protoreflect.FileDescriptor
 
 const file_protos_jira_proto_rawDesc = "" +
@@ -308,26 +913,80 @@ const file_protos_jira_proto_rawDesc = "" +
 	"\x11protos/jira.proto\x12\x04jira\".\n" +
 	"\vSyncRequest\x12\x1f\n" +
 	"\vproject_key\x18\x01 \x01(\tR\n" +
-	"projectKey\"L\n" +
+	"projectKey\"\x91\x01\n" +
 	"\x11CreateCardRequest\x12\x1f\n" +
 	"\vproject_key\x18\x01 \x01(\tR\n" +
 	"projectKey\x12\x16\n" +
-	"\x06prompt\x18\x02 \x01(\tR\x06prompt\"&\n" +
+	"\x06prompt\x18\x02 \x01(\tR\x06prompt\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\"&\n" +
 	"\fSyncResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\"I\n" +
 	"\x12CreateCardResponse\x12\x1b\n" +
 	"\tissue_key\x18\x01 \x01(\tR\bissueKey\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\"(\n" +
 	"\x0eMessageRequest\x12\x16\n" +
-	"\x06prompt\x18\x01 \x01(\tR\x06prompt\"+\n" +
+	"\x06prompt\x18\x01 \x01(\tR\x06prompt\"c\n" +
 	"\x0fMessageResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\xbb\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x16\n" +
+	"\x06intent\x18\x02 \x01(\tR\x06intent\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\"\xb8\x01\n" +
+	"\x13SearchIssuesRequest\x12\x10\n" +
+	"\x03jql\x18\x01 \x01(\tR\x03jql\x12\x1a\n" +
+	"\bquestion\x18\x02 \x01(\tR\bquestion\x12\x1f\n" +
+	"\vproject_key\x18\x03 \x01(\tR\n" +
+	"projectKey\x12\x19\n" +
+	"\bstart_at\x18\x04 \x01(\x05R\astartAt\x12\x1f\n" +
+	"\vmax_results\x18\x05 \x01(\x05R\n" +
+	"maxResults\x12\x16\n" +
+	"\x06fields\x18\x06 \x03(\tR\x06fields\"\xa2\x01\n" +
+	"\x14SearchIssuesResponse\x12\x10\n" +
+	"\x03jql\x18\x01 \x01(\tR\x03jql\x12#\n" +
+	"\x06issues\x18\x02 \x03(\v2\v.jira.IssueR\x06issues\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12\x19\n" +
+	"\bstart_at\x18\x04 \x01(\x05R\astartAt\x12\"\n" +
+	"\rnext_start_at\x18\x05 \x01(\x05R\vnextStartAt\".\n" +
+	"\x0fGetIssueRequest\x12\x1b\n" +
+	"\tissue_key\x18\x01 \x01(\tR\bissueKey\"\xcf\x01\n" +
+	"\x10GetIssueResponse\x12!\n" +
+	"\x05issue\x18\x01 \x01(\v2\v.jira.IssueR\x05issue\x12)\n" +
+	"\bcomments\x18\x02 \x03(\v2\r.jira.CommentR\bcomments\x12%\n" +
+	"\x05links\x18\x03 \x03(\v2\x0f.jira.IssueLinkR\x05links\x12'\n" +
+	"\bsubtasks\x18\x04 \x03(\v2\v.jira.IssueR\bsubtasks\x12\x1d\n" +
+	"\n" +
+	"parent_key\x18\x05 \x01(\tR\tparentKey\"_\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\tR\acreated\"J\n" +
+	"\tIssueLink\x12\x1a\n" +
+	"\brelation\x18\x01 \x01(\tR\brelation\x12!\n" +
+	"\x05issue\x18\x02 \x01(\v2\v.jira.IssueR\x05issue\"\xac\x02\n" +
+	"\x05Issue\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"issue_type\x18\x04 \x01(\tR\tissueType\x12\x1a\n" +
+	"\bassignee\x18\x05 \x01(\tR\bassignee\x12\x1a\n" +
+	"\breporter\x18\x06 \x01(\tR\breporter\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12\x16\n" +
+	"\x06labels\x18\b \x03(\tR\x06labels\x12\x18\n" +
+	"\acreated\x18\t \x01(\tR\acreated\x12\x18\n" +
+	"\aupdated\x18\n" +
+	" \x01(\tR\aupdated\x12 \n" +
+	"\vdescription\x18\v \x01(\tR\vdescription2\xbd\x02\n" +
 	"\vJiraService\x123\n" +
 	"\n" +
 	"SyncIssues\x12\x11.jira.SyncRequest\x1a\x12.jira.SyncResponse\x12?\n" +
 	"\n" +
 	"CreateCard\x12\x17.jira.CreateCardRequest\x1a\x18.jira.CreateCardResponse\x126\n" +
-	"\aMessage\x12\x14.jira.MessageRequest\x1a\x15.jira.MessageResponseB\rZ\v../gen;jirab\x06proto3"
+	"\aMessage\x12\x14.jira.MessageRequest\x1a\x15.jira.MessageResponse\x12E\n" +
+	"\fSearchIssues\x12\x19.jira.SearchIssuesRequest\x1a\x1a.jira.SearchIssuesResponse\x129\n" +
+	"\bGetIssue\x12\x15.jira.GetIssueRequest\x1a\x16.jira.GetIssueResponseB\rZ\v../gen;jirab\x06proto3"
 
 var (
 	file_protos_jira_proto_rawDescOnce sync.Once
@@ -341,27 +1000,44 @@ func file_protos_jira_proto_rawDescGZIP() []byte {
 	return file_protos_jira_proto_rawDescData
 }
 
-var file_protos_jira_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_protos_jira_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_protos_jira_proto_goTypes = []any{
-	(*SyncRequest)(nil),        // 0: jira.SyncRequest
-	(*CreateCardRequest)(nil),  // 1: jira.CreateCardRequest
-	(*SyncResponse)(nil),       // 2: jira.SyncResponse
-	(*CreateCardResponse)(nil), // 3: jira.CreateCardResponse
-	(*MessageRequest)(nil),     // 4: jira.MessageRequest
-	(*MessageResponse)(nil),    // 5: jira.MessageResponse
+	(*SyncRequest)(nil),          // 0: jira.SyncRequest
+	(*CreateCardRequest)(nil),    // 1: jira.CreateCardRequest
+	(*SyncResponse)(nil),         // 2: jira.SyncResponse
+	(*CreateCardResponse)(nil),   // 3: jira.CreateCardResponse
+	(*MessageRequest)(nil),       // 4: jira.MessageRequest
+	(*MessageResponse)(nil),      // 5: jira.MessageResponse
+	(*SearchIssuesRequest)(nil),  // 6: jira.SearchIssuesRequest
+	(*SearchIssuesResponse)(nil), // 7: jira.SearchIssuesResponse
+	(*GetIssueRequest)(nil),      // 8: jira.GetIssueRequest
+	(*GetIssueResponse)(nil),     // 9: jira.GetIssueResponse
+	(*Comment)(nil),              // 10: jira.Comment
+	(*IssueLink)(nil),            // 11: jira.IssueLink
+	(*Issue)(nil),                // 12: jira.Issue
 }
 var file_protos_jira_proto_depIdxs = []int32{
-	0, // 0: jira.JiraService.SyncIssues:input_type -> jira.SyncRequest
-	1, // 1: jira.JiraService.CreateCard:input_type -> jira.CreateCardRequest
-	4, // 2: jira.JiraService.Message:input_type -> jira.MessageRequest
-	2, // 3: jira.JiraService.SyncIssues:output_type -> jira.SyncResponse
-	3, // 4: jira.JiraService.CreateCard:output_type -> jira.CreateCardResponse
-	5, // 5: jira.JiraService.Message:output_type -> jira.MessageResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	12, // 0: jira.SearchIssuesResponse.issues:type_name -> jira.Issue
+	12, // 1: jira.GetIssueResponse.issue:type_name -> jira.Issue
+	10, // 2: jira.GetIssueResponse.comments:type_name -> jira.Comment
+	11, // 3: jira.GetIssueResponse.links:type_name -> jira.IssueLink
+	12, // 4: jira.GetIssueResponse.subtasks:type_name -> jira.Issue
+	12, // 5: jira.IssueLink.issue:type_name -> jira.Issue
+	0,  // 6: jira.JiraService.SyncIssues:input_type -> jira.SyncRequest
+	1,  // 7: jira.JiraService.CreateCard:input_type -> jira.CreateCardRequest
+	4,  // 8: jira.JiraService.Message:input_type -> jira.MessageRequest
+	6,  // 9: jira.JiraService.SearchIssues:input_type -> jira.SearchIssuesRequest
+	8,  // 10: jira.JiraService.GetIssue:input_type -> jira.GetIssueRequest
+	2,  // 11: jira.JiraService.SyncIssues:output_type -> jira.SyncResponse
+	3,  // 12: jira.JiraService.CreateCard:output_type -> jira.CreateCardResponse
+	5,  // 13: jira.JiraService.Message:output_type -> jira.MessageResponse
+	7,  // 14: jira.JiraService.SearchIssues:output_type -> jira.SearchIssuesResponse
+	9,  // 15: jira.JiraService.GetIssue:output_type -> jira.GetIssueResponse
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_protos_jira_proto_init() }
@@ -375,7 +1051,7 @@ func file_protos_jira_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_jira_proto_rawDesc), len(file_protos_jira_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
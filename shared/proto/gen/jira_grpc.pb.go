@@ -19,18 +19,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	JiraService_SyncIssues_FullMethodName = "/jira.JiraService/SyncIssues"
-	JiraService_CreateCard_FullMethodName = "/jira.JiraService/CreateCard"
-	JiraService_Message_FullMethodName    = "/jira.JiraService/Message"
+	JiraService_SyncIssues_FullMethodName   = "/jira.JiraService/SyncIssues"
+	JiraService_CreateCard_FullMethodName   = "/jira.JiraService/CreateCard"
+	JiraService_Message_FullMethodName      = "/jira.JiraService/Message"
+	JiraService_SearchIssues_FullMethodName = "/jira.JiraService/SearchIssues"
+	JiraService_GetIssue_FullMethodName     = "/jira.JiraService/GetIssue"
 )
 
 // JiraServiceClient is the client API for JiraService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Requests are validated before any LLM or Jira call; a violation returns
+// INVALID_ARGUMENT with a google.rpc.BadRequest listing every failing field.
 type JiraServiceClient interface {
 	SyncIssues(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
 	CreateCard(ctx context.Context, in *CreateCardRequest, opts ...grpc.CallOption) (*CreateCardResponse, error)
 	Message(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	SearchIssues(ctx context.Context, in *SearchIssuesRequest, opts ...grpc.CallOption) (*SearchIssuesResponse, error)
+	GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*GetIssueResponse, error)
 }
 
 type jiraServiceClient struct {
@@ -71,13 +78,38 @@ func (c *jiraServiceClient) Message(ctx context.Context, in *MessageRequest, opt
 	return out, nil
 }
 
+func (c *jiraServiceClient) SearchIssues(ctx context.Context, in *SearchIssuesRequest, opts ...grpc.CallOption) (*SearchIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchIssuesResponse)
+	err := c.cc.Invoke(ctx, JiraService_SearchIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jiraServiceClient) GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*GetIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIssueResponse)
+	err := c.cc.Invoke(ctx, JiraService_GetIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // JiraServiceServer is the server API for JiraService service.
 // All implementations must embed UnimplementedJiraServiceServer
 // for forward compatibility.
+//
+// Requests are validated before any LLM or Jira call; a violation returns
+// INVALID_ARGUMENT with a google.rpc.BadRequest listing every failing field.
 type JiraServiceServer interface {
 	SyncIssues(context.Context, *SyncRequest) (*SyncResponse, error)
 	CreateCard(context.Context, *CreateCardRequest) (*CreateCardResponse, error)
 	Message(context.Context, *MessageRequest) (*MessageResponse, error)
+	SearchIssues(context.Context, *SearchIssuesRequest) (*SearchIssuesResponse, error)
+	GetIssue(context.Context, *GetIssueRequest) (*GetIssueResponse, error)
 	mustEmbedUnimplementedJiraServiceServer()
 }
 
@@ -97,6 +129,12 @@ func (UnimplementedJiraServiceServer) CreateCard(context.Context, *CreateCardReq
 func (UnimplementedJiraServiceServer) Message(context.Context, *MessageRequest) (*MessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Message not implemented")
 }
+func (UnimplementedJiraServiceServer) SearchIssues(context.Context, *SearchIssuesRequest) (*SearchIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchIssues not implemented")
+}
+func (UnimplementedJiraServiceServer) GetIssue(context.Context, *GetIssueRequest) (*GetIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssue not implemented")
+}
 func (UnimplementedJiraServiceServer) mustEmbedUnimplementedJiraServiceServer() {}
 func (UnimplementedJiraServiceServer) testEmbeddedByValue()                     {}
 
@@ -172,6 +210,42 @@ func _JiraService_Message_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JiraService_SearchIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JiraServiceServer).SearchIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JiraService_SearchIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JiraServiceServer).SearchIssues(ctx, req.(*SearchIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JiraService_GetIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JiraServiceServer).GetIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JiraService_GetIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JiraServiceServer).GetIssue(ctx, req.(*GetIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // JiraService_ServiceDesc is the grpc.ServiceDesc for JiraService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +265,14 @@ var JiraService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Message",
 			Handler:    _JiraService_Message_Handler,
 		},
+		{
+			MethodName: "SearchIssues",
+			Handler:    _JiraService_SearchIssues_Handler,
+		},
+		{
+			MethodName: "GetIssue",
+			Handler:    _JiraService_GetIssue_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protos/jira.proto",
@@ -0,0 +1,101 @@
+// Package tlsclient configures TLS for the clients of mcp-server-jira:
+// mcphost, api-gateway and the server's own health command.
+package tlsclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Environment variables read by FromEnv. The client certificate has its own
+// names so they never collide with the server's MCP_TLS_CERT_FILE and
+// MCP_TLS_KEY_FILE.
+const (
+	EnvEnabled    = "MCP_TLS_ENABLED"
+	EnvCAFile     = "MCP_TLS_CA_FILE"
+	EnvCertFile   = "MCP_TLS_CLIENT_CERT_FILE"
+	EnvKeyFile    = "MCP_TLS_CLIENT_KEY_FILE"
+	EnvServerName = "MCP_TLS_SERVER_NAME"
+)
+
+// Config describes how a client authenticates the server and, for mutual
+// TLS, itself.
+type Config struct {
+	Enabled    bool
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// FromEnv reads the Env* variables. Setting any file enables TLS.
+func FromEnv() Config {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvEnabled))
+	cfg := Config{
+		CAFile:     os.Getenv(EnvCAFile),
+		CertFile:   os.Getenv(EnvCertFile),
+		KeyFile:    os.Getenv(EnvKeyFile),
+		ServerName: os.Getenv(EnvServerName),
+	}
+	cfg.Enabled = enabled || cfg.CAFile != "" || cfg.CertFile != ""
+	return cfg
+}
+
+// TLS builds the client TLS settings, or returns nil when TLS is disabled.
+// Without a CA file the system roots verify the server.
+func (c Config) TLS() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		pool, err := LoadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// DialOption returns the gRPC transport credentials for this configuration.
+func (c Config) DialOption() (grpc.DialOption, error) {
+	cfg, err := c.TLS()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
+}
+
+// LoadCertPool reads PEM-encoded CA certificates from path.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}